@@ -0,0 +1,142 @@
+package dec64
+
+import "math/big"
+
+// ToBigInt returns coefficient and exponent of d: d is coef*10^exp.
+func ToBigInt(d Dec64) (coef *big.Int, exp int) {
+	return big.NewInt(d.Coefficient()), d.Exponent()
+}
+
+// FromBigInt returns coef*10^exp as a dec64.
+// ErrInexact is returned along with the rounded value (half up)
+// when coefficient has too many digits, ErrRange when too big.
+func FromBigInt(coef *big.Int, exp int) (Dec64, error) {
+	return fitBig(coef.Sign() < 0, new(big.Int).Abs(coef), exp, false, RoundHalfUp)
+}
+
+// ToScaledBigInt returns d*10^scale as an integer rounded with mode,
+// as ToScaledInt does without range limit.
+// ErrInexact is returned along with the rounded value when digits are lost,
+// ErrRange when d is not a number.
+func ToScaledBigInt(d Dec64, scale int, mode RoundingMode) (*big.Int, error) {
+	if isSpecial(d) {
+		return nil, ErrRange
+	}
+	coef, exp := ToBigInt(d)
+	k := exp + scale
+	if k >= 0 {
+		return coef.Mul(coef, pow10(k)), nil
+	}
+	neg := coef.Sign() < 0
+	q, rem := coef.QuoRem(coef, pow10(k), new(big.Int))
+	if rem.Sign() == 0 {
+		return q, nil
+	}
+	// first dropped digit and sticky ones
+	rem.Abs(rem)
+	rd, sticky := rem.QuoRem(rem, pow10(-k-1), new(big.Int))
+	if mode.roundAway(neg, q.Bit(0) == 1, rd.Uint64(), sticky.Sign() != 0) {
+		if neg {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q, ErrInexact
+}
+
+// ToBigRat returns d as an exact rational,
+// nil for Empty, NotAvailable and NaN.
+func ToBigRat(d Dec64) *big.Rat {
+	if isSpecial(d) {
+		return nil
+	}
+	coef, exp := ToBigInt(d)
+	r := new(big.Rat).SetInt(coef)
+	if exp == 0 {
+		return r
+	}
+	p := new(big.Rat).SetInt(pow10(exp))
+	if exp > 0 {
+		return r.Mul(r, p)
+	}
+	return r.Quo(r, p)
+}
+
+// FromBigRat returns r as a dec64 rounded with mode.
+// ErrInexact is returned along with the rounded value when digits are lost,
+// ErrRange when r is too big.
+func FromBigRat(r *big.Rat, mode RoundingMode) (Dec64, error) {
+	if r.Sign() == 0 {
+		return 0, nil
+	}
+	num := new(big.Int).Abs(r.Num())
+	den := new(big.Int).Set(r.Denom())
+	// scale to get at least 18 digits in quotient
+	// so that rounding is done by fit
+	k := 18 - (num.BitLen()-1-den.BitLen())*30103/100000
+	if k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+	q, rem := num.QuoRem(num, den, new(big.Int))
+	d, err := fitBig(r.Sign() < 0, q, -k, rem.Sign() != 0, mode)
+	if err == ErrRange {
+		return d, err
+	}
+	return Normalize(d), err
+}
+
+// ToBigFloat returns d as a big.Float of precision prec,
+// nil for Empty, NotAvailable and NaN.
+// Result Acc tells if conversion was exact.
+func ToBigFloat(d Dec64, prec uint) *big.Float {
+	r := ToBigRat(d)
+	if r == nil {
+		return nil
+	}
+	return new(big.Float).SetPrec(prec).SetRat(r)
+}
+
+// FromBigFloat returns f as a dec64 rounded with mode.
+// ErrInexact is returned along with the rounded value when digits are lost,
+// ErrRange when f is infinite or too big.
+func FromBigFloat(f *big.Float, mode RoundingMode) (Dec64, error) {
+	if f.IsInf() {
+		return Empty, ErrRange
+	}
+	r, _ := f.Rat(nil)
+	return FromBigRat(r, mode)
+}
+
+// pow10 returns 10^n as a big.Int, n must be positive.
+func pow10(n int) *big.Int {
+	if n < 0 {
+		n = -n
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// fitBig is fit for a big coefficient, c must be positive.
+func fitBig(neg bool, c *big.Int, exp int, sticky bool, mode RoundingMode) (Dec64, error) {
+	if c.BitLen() > 63 {
+		// drop digits in bulk, at least one will be dropped
+		// by fit which takes care of rounding
+		n := (c.BitLen() - 63) * 30103 / 100000
+		r := new(big.Int)
+		c = new(big.Int).Set(c)
+		if n > 0 {
+			c.QuoRem(c, pow10(n), r)
+			sticky = sticky || r.Sign() != 0
+			exp += n
+		}
+		ten := big.NewInt(10)
+		for c.BitLen() > 63 {
+			c.QuoRem(c, ten, r)
+			sticky = sticky || r.Sign() != 0
+			exp++
+		}
+	}
+	return fit(neg, c.Uint64(), exp, sticky, mode)
+}
@@ -0,0 +1,79 @@
+package dec64
+
+import (
+	"math/big"
+	"testing"
+)
+
+func testOneBigRat(t *testing.T, r string, mode RoundingMode, ref string, referr error) {
+	br, ok := new(big.Rat).SetString(r)
+	if !ok {
+		t.Errorf("wrong rational %s", r)
+		return
+	}
+	d, err := FromBigRat(br, mode)
+	if err != referr {
+		t.Errorf("FromBigRat(%s) error is %v should be %v", r, err, referr)
+	}
+	if d.String() != ref {
+		t.Errorf("FromBigRat(%s) is %s should be %s", r, d, ref)
+	}
+	if err == nil && ToBigRat(d).Cmp(br) != 0 {
+		t.Errorf("ToBigRat(%s) is %s should be %s", d, ToBigRat(d), r)
+	}
+}
+
+func TestBigRat(t *testing.T) {
+	testOneBigRat(t, "1/4", RoundHalfUp, "0.25", nil)
+	testOneBigRat(t, "-1/32", RoundHalfUp, "-0.03125", nil)
+	testOneBigRat(t, "10125/100", RoundHalfUp, "101.25", nil)
+	testOneBigRat(t, "1/3", RoundHalfUp, "0.33333333333333333", ErrInexact)
+	testOneBigRat(t, "2/3", RoundHalfUp, "0.6666666666666667", ErrInexact)
+	testOneBigRat(t, "2/3", RoundDown, "0.6666666666666666", ErrInexact)
+	testOneBigRat(t, "-2/3", RoundFloor, "-0.6666666666666667", ErrInexact)
+	testOneBigRat(t, "123456789012345678901234567890", RoundHalfUp,
+		"123456789012345680000000000000", ErrInexact)
+	testOneBigRat(t, "1/1000000000000000000000000000000000000000000000000000000000000000000000", RoundHalfUp,
+		"0.000000000000000000000000000000000000000000000000000000000000000000001", nil)
+	testOneBigRat(t, "0", RoundHalfUp, "0", nil)
+	big := new(big.Rat).SetInt(pow10(200))
+	if d, err := FromBigRat(big, RoundHalfUp); err != ErrRange {
+		t.Errorf("FromBigRat(1e200) is %s, %v should be in error", d, err)
+	}
+	if ToBigRat(Empty) != nil {
+		t.Errorf("ToBigRat(Empty) should be nil")
+	}
+}
+
+func TestBigIntFloat(t *testing.T) {
+	for _, s := range sVBench {
+		d, err := Parse(s)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		coef, exp := ToBigInt(d)
+		back, err := FromBigInt(coef, exp)
+		if err != nil || back != d {
+			t.Errorf("FromBigInt(ToBigInt(%s)) is %s, %v", s, back, err)
+		}
+		f := ToBigFloat(d, 200)
+		back, err = FromBigFloat(f, RoundHalfEven)
+		if !back.Equal(d) {
+			t.Errorf("FromBigFloat(ToBigFloat(%s)) is %s, %v", s, back, err)
+		}
+	}
+	coef, _ := new(big.Int).SetString("-98765432109876543210", 10)
+	d, err := FromBigInt(coef, -2)
+	if err != ErrInexact || d.String() != "-987654321098765400" {
+		t.Errorf("FromBigInt(%s) is %s, %v", coef, d, err)
+	}
+	f := ToBigFloat(Dec64(1*256+255), 53)
+	if f.Acc() == big.Exact {
+		t.Errorf("ToBigFloat(0.1) should not be exact")
+	}
+	f = ToBigFloat(Dec64(25*256+254), 53)
+	if f.Acc() != big.Exact {
+		t.Errorf("ToBigFloat(0.25) should be exact")
+	}
+}
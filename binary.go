@@ -0,0 +1,97 @@
+package dec64
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// binaryVersion first byte of MarshalBinary output.
+const binaryVersion = 1
+
+// AppendVarint appends d to b as a zigzag varint coefficient
+// followed by the exponent byte, 123.45 takes 4 bytes instead of 8.
+func AppendVarint(b []byte, d Dec64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], d.Coefficient())
+	b = append(b, buf[:n]...)
+	return append(b, byte(d))
+}
+
+// ReadVarint reads a dec64 written by AppendVarint.
+// io.EOF is returned only if no byte was read.
+func ReadVarint(r io.ByteReader) (d Dec64, err error) {
+	coef, err := binary.ReadVarint(r)
+	if err != nil {
+		return
+	}
+	exp, err := r.ReadByte()
+	if err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	if coef > MaxCoefficient || coef < MinCoefficient {
+		err = ErrRange
+		return
+	}
+	d = word(coef, int(int8(exp)))
+	return
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler (and gob encoding):
+// a version byte followed by the varint encoding.
+func (d Dec64) MarshalBinary() ([]byte, error) {
+	return AppendVarint([]byte{binaryVersion}, d), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *Dec64) UnmarshalBinary(data []byte) (err error) {
+	if len(data) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("Unknown dec64 binary version %d", data[0])
+	}
+	r := bytes.NewReader(data[1:])
+	v, err := ReadVarint(r)
+	if err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%d trailing bytes after dec64", r.Len())
+	}
+	*d = v
+	return
+}
+
+// readFull reads len(buf) bytes from r,
+// io.EOF becomes io.ErrUnexpectedEOF.
+func readFull(r io.ByteReader, buf []byte) (err error) {
+	for i := range buf {
+		buf[i], err = r.ReadByte()
+		if err != nil {
+			err = eofUnexpected(err)
+			return
+		}
+	}
+	return
+}
+
+// eofUnexpected returns io.ErrUnexpectedEOF for io.EOF.
+func eofUnexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// byteReader returns r as an io.ByteReader, buffering it when needed.
+func byteReader(r io.Reader) io.ByteReader {
+	if br, ok := r.(io.ByteReader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
@@ -0,0 +1,94 @@
+package dec64
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"testing"
+)
+
+func testVarint(t *testing.T, s string, size int) {
+	d, _ := Parse(s)
+	b := AppendVarint(nil, d)
+	if len(b) != size {
+		t.Errorf("%s takes %d bytes should be %d", s, len(b), size)
+	}
+	res, err := ReadVarint(bytes.NewReader(b))
+	if err != nil || res != d {
+		t.Errorf("%s read as %s (%v)", s, res, err)
+	}
+}
+
+func TestVarint(t *testing.T) {
+	testVarint(t, "0", 2)
+	testVarint(t, "123.45", 4)
+	testVarint(t, "1.25", 3)
+	testVarint(t, "-1.5", 2)
+	testVarint(t, "", 2)
+	testVarint(t, "36028797018963967", 9)
+	testVarint(t, "-36028797018963968", 9)
+	// specials
+	for _, d := range []Dec64{NotAvailable, NaN} {
+		res, err := ReadVarint(bytes.NewReader(AppendVarint(nil, d)))
+		if err != nil || res != d {
+			t.Errorf("%x read as %x (%v)", int64(d), int64(res), err)
+		}
+	}
+	// stream of values
+	var b []byte
+	values := []Dec64{Dec64(12345*256 + 254), Empty, Dec64(-7 * 256)}
+	for _, d := range values {
+		b = AppendVarint(b, d)
+	}
+	r := bytes.NewReader(b)
+	for _, d := range values {
+		if res, err := ReadVarint(r); err != nil || res != d {
+			t.Errorf("%s read as %s (%v)", d, res, err)
+		}
+	}
+	if _, err := ReadVarint(r); err != io.EOF {
+		t.Errorf("End of stream gives %v should be EOF", err)
+	}
+	if _, err := ReadVarint(bytes.NewReader(b[:1])); err != io.ErrUnexpectedEOF {
+		t.Errorf("Missing exponent gives %v", err)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	d, _ := Parse("-98.765")
+	b, err := d.MarshalBinary()
+	if err != nil || b[0] != binaryVersion {
+		t.Errorf("MarshalBinary gives %v %v", b, err)
+	}
+	var res Dec64
+	if err = res.UnmarshalBinary(b); err != nil || res != d {
+		t.Errorf("UnmarshalBinary gives %s (%v)", res, err)
+	}
+	if err = res.UnmarshalBinary(append(b, 0)); err == nil {
+		t.Errorf("Trailing byte should be an error")
+	}
+	if err = res.UnmarshalBinary([]byte{2, 0, 0}); err == nil {
+		t.Errorf("Unknown version should be an error")
+	}
+	if err = res.UnmarshalBinary(b[:1]); err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated gives %v", err)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type quote struct {
+		Bid, Ask Dec64
+	}
+	var q quote
+	q.Bid, _ = Parse("1.0825")
+	q.Ask = Empty
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(q); err != nil {
+		t.Errorf("Encode: %v", err)
+		return
+	}
+	var res quote
+	if err := gob.NewDecoder(&buf).Decode(&res); err != nil || res != q {
+		t.Errorf("Decode gives %+v (%v)", res, err)
+	}
+}
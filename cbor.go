@@ -0,0 +1,292 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// CBOR major types
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborArray  = 4
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// CBOR tags and simple values used for dec64.
+const (
+	cborTagPosBignum = 2
+	cborTagNegBignum = 3
+	// cborTagDecimal decimal fraction [exponent, mantissa]
+	cborTagDecimal = 4
+	cborNull       = 0xf6
+	cborUndefined  = 0xf7
+	cborBreak      = 0xff
+	// cborIndefinite additional info of indefinite length items
+	cborIndefinite = 31
+)
+
+// appendCBORHead appends an item head of major type with argument v.
+func appendCBORHead(b []byte, major byte, v uint64) []byte {
+	major <<= 5
+	switch {
+	case v < 24:
+		return append(b, major|byte(v))
+	case v <= 0xff:
+		return append(b, major|24, byte(v))
+	case v <= 0xffff:
+		return append(b, major|25, byte(v>>8), byte(v))
+	case v <= 0xffffffff:
+		return append(b, major|26, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+	b = append(b, major|27, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(b[len(b)-8:], v)
+	return b
+}
+
+// appendCBORInt appends v as an unsigned or negative integer.
+func appendCBORInt(b []byte, v int64) []byte {
+	if v < 0 {
+		return appendCBORHead(b, cborNegInt, uint64(-1-v))
+	}
+	return appendCBORHead(b, cborUint, uint64(v))
+}
+
+// AppendCBOR appends d to b as a CBOR decimal fraction,
+// tag 4 [exponent, mantissa]. Empty is null, NotAvailable undefined
+// and NaN a half precision NaN.
+func AppendCBOR(b []byte, d Dec64) []byte {
+	switch {
+	case d == Empty:
+		return append(b, cborNull)
+	case d == NotAvailable:
+		return append(b, cborUndefined)
+	case d.IsNaN():
+		return append(b, 0xf9, 0x7e, 0x00)
+	}
+	b = appendCBORHead(b, cborTag, cborTagDecimal)
+	b = appendCBORHead(b, cborArray, 2)
+	b = appendCBORInt(b, int64(d.Exponent()))
+	return appendCBORInt(b, d.Coefficient())
+}
+
+// readCBORHead reads major type, additional info and argument of an item.
+// Argument of indefinite length items and simple values is 0.
+func readCBORHead(r io.ByteReader) (major, info byte, v uint64, err error) {
+	ib, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	return cborHead(r, ib)
+}
+
+// cborHead reads argument of an item starting with initial byte ib.
+func cborHead(r io.ByteReader, ib byte) (major, info byte, v uint64, err error) {
+	major, info = ib>>5, ib&0x1f
+	switch {
+	case info < 24:
+		v = uint64(info)
+	case info <= 27:
+		var buf [8]byte
+		n := 1 << (info - 24)
+		if err = readFull(r, buf[8-n:]); err != nil {
+			return
+		}
+		v = binary.BigEndian.Uint64(buf[:])
+	case info != cborIndefinite:
+		err = fmt.Errorf("Invalid CBOR additional info %d", info)
+	}
+	return
+}
+
+// readCBORInt reads an integer or a bignum as a big.Int.
+func readCBORInt(r io.ByteReader) (*big.Int, error) {
+	ib, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	return cborInt(r, ib)
+}
+
+// cborInt reads an integer or a bignum starting with initial byte ib.
+func cborInt(r io.ByteReader, ib byte) (*big.Int, error) {
+	major, info, v, err := cborHead(r, ib)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case major == cborUint && info != cborIndefinite:
+		return new(big.Int).SetUint64(v), nil
+	case major == cborNegInt && info != cborIndefinite:
+		// -1-v
+		n := new(big.Int).SetUint64(v)
+		return n.Neg(n.Add(n, big.NewInt(1))), nil
+	case major == cborTag && (v == cborTagPosBignum || v == cborTagNegBignum):
+		bmajor, binfo, size, err := readCBORHead(r)
+		if err != nil {
+			return nil, eofUnexpected(err)
+		}
+		if bmajor != cborBytes || binfo == cborIndefinite || size > 1<<16 {
+			return nil, fmt.Errorf("Invalid CBOR bignum")
+		}
+		buf := make([]byte, size)
+		if err = readFull(r, buf); err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(buf)
+		if v == cborTagNegBignum {
+			n.Neg(n.Add(n, big.NewInt(1)))
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("Unexpected CBOR major type %d for an integer", major)
+}
+
+// ReadCBOR reads a dec64 written by AppendCBOR. Integers, bignums
+// and decimal fractions with bignum mantissa are accepted too:
+// ErrInexact is returned along with the rounded value when
+// mantissa has too many digits, ErrRange when too big.
+// io.EOF is returned only if no byte was read.
+func ReadCBOR(r io.ByteReader) (d Dec64, err error) {
+	ib, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	return cborDec64(r, ib)
+}
+
+// cborDec64 reads a dec64 starting with initial byte ib.
+func cborDec64(r io.ByteReader, ib byte) (d Dec64, err error) {
+	switch ib {
+	case cborNull:
+		return Empty, nil
+	case cborUndefined:
+		return NotAvailable, nil
+	case 0xf9, 0xfa, 0xfb:
+		return readCBORNaN(r, ib)
+	}
+	if ib != cborTag<<5|cborTagDecimal {
+		// plain integer
+		var n *big.Int
+		if n, err = cborInt(r, ib); err != nil {
+			err = eofUnexpected(err)
+			return
+		}
+		return fromBigExact(n, 0)
+	}
+	major, info, size, err := readCBORHead(r)
+	if err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	if major != cborArray || info == cborIndefinite || size != 2 {
+		err = fmt.Errorf("CBOR decimal fraction is not a 2 items array")
+		return
+	}
+	exp, err := readCBORInt(r)
+	if err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	mant, err := readCBORInt(r)
+	if err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	if !exp.IsInt64() || exp.Int64() > 1<<16 || exp.Int64() < -1<<16 {
+		return Empty, ErrRange
+	}
+	return fromBigExact(mant, int(exp.Int64()))
+}
+
+// fromBigExact returns mant*10^exp, exactly when it fits.
+func fromBigExact(mant *big.Int, exp int) (Dec64, error) {
+	if mant.IsInt64() {
+		if d, err := New(mant.Int64(), exp); err == nil {
+			return d, nil
+		}
+	}
+	return FromBigInt(mant, exp)
+}
+
+// readCBORNaN reads a float following ib, only NaN is a dec64.
+func readCBORNaN(r io.ByteReader, ib byte) (Dec64, error) {
+	buf := make([]byte, 1<<(ib-0xf8))
+	if err := readFull(r, buf); err != nil {
+		return Empty, err
+	}
+	nan := false
+	switch len(buf) {
+	case 2:
+		v := binary.BigEndian.Uint16(buf)
+		nan = v&0x7c00 == 0x7c00 && v&0x3ff != 0
+	case 4:
+		v := binary.BigEndian.Uint32(buf)
+		nan = v&0x7f800000 == 0x7f800000 && v&0x7fffff != 0
+	case 8:
+		v := binary.BigEndian.Uint64(buf)
+		nan = v&0x7ff0000000000000 == 0x7ff0000000000000 && v&0xfffffffffffff != 0
+	}
+	if !nan {
+		return Empty, fmt.Errorf("CBOR float is not a dec64")
+	}
+	return NaN, nil
+}
+
+// ListToCBOR sends list of dec64 to writer as a CBOR array.
+func ListToCBOR(w io.Writer, values []Dec64) (err error) {
+	buff := appendCBORHead(make([]byte, 0, 256), cborArray, uint64(len(values)))
+	for _, v := range values {
+		buff = AppendCBOR(buff, v)
+		if len(buff) >= 240 {
+			if _, err = w.Write(buff); err != nil {
+				return
+			}
+			buff = buff[:0]
+		}
+	}
+	_, err = w.Write(buff)
+	return
+}
+
+// ListFromCBOR returns list of dec64 from a CBOR array,
+// definite or indefinite length.
+// Reading stops on first error but ErrInexact which is reported
+// once all values are read. Errors are wrapped with the index
+// of the value, use errors.Is.
+func ListFromCBOR(r io.Reader) (values []Dec64, err error) {
+	br := byteReader(r)
+	major, info, size, err := readCBORHead(br)
+	if err != nil {
+		return
+	}
+	if major != cborArray {
+		err = fmt.Errorf("Unexpected CBOR major type %d for a list", major)
+		return
+	}
+	n := -1
+	if info != cborIndefinite {
+		n = int(size)
+	}
+	values = make([]Dec64, 0, 16)
+	_, err = convertAll(n, func(i int) error {
+		ib, err := br.ReadByte()
+		if err != nil {
+			return eofUnexpected(err)
+		}
+		if n < 0 && ib == cborBreak {
+			return io.EOF
+		}
+		d, err := cborDec64(br, ib)
+		if err != nil && err != ErrInexact {
+			return eofUnexpected(err)
+		}
+		values = append(values, d)
+		return err
+	})
+	return
+}
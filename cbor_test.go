@@ -0,0 +1,89 @@
+package dec64
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func testCBOR(t *testing.T, d Dec64, ref []byte) {
+	b := AppendCBOR(nil, d)
+	if !bytes.Equal(b, ref) {
+		t.Errorf("%s encoded as %x should be %x", d, b, ref)
+	}
+	res, err := ReadCBOR(bytes.NewReader(b))
+	if err != nil || res != d {
+		t.Errorf("%x decoded as %s (%v) should be %s", b, res, err, d)
+	}
+}
+
+func TestCBOR(t *testing.T) {
+	// RFC 8949 example 273.15 is 4([-2, 27315])
+	testCBOR(t, Dec64(27315*256+254), []byte{0xc4, 0x82, 0x21, 0x19, 0x6a, 0xb3})
+	testCBOR(t, Dec64(-5*256+1), []byte{0xc4, 0x82, 0x01, 0x24})
+	testCBOR(t, Empty, []byte{0xf6})
+	testCBOR(t, NotAvailable, []byte{0xf7})
+	testCBOR(t, NaN, []byte{0xf9, 0x7e, 0x00})
+	testCBOR(t, Dec64(MinCoefficient*256), []byte{0xc4, 0x82, 0x00,
+		0x3b, 0x00, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	// zero coefficient whatever exponent is 0
+	res, err := ReadCBOR(bytes.NewReader([]byte{0xc4, 0x82, 0x01, 0x00}))
+	if err != nil || res != 0 {
+		t.Errorf("0e1 decoded as %#x (%v) should be 0", int64(res), err)
+	}
+	// plain integer
+	res, err = ReadCBOR(bytes.NewReader([]byte{0x38, 0x63}))
+	if err != nil || res.String() != "-100" {
+		t.Errorf("-100 decoded as %s (%v)", res, err)
+	}
+	// bignum mantissa 2^64 with exponent -1
+	res, err = ReadCBOR(bytes.NewReader([]byte{0xc4, 0x82, 0x20,
+		0xc2, 0x49, 0x01, 0, 0, 0, 0, 0, 0, 0, 0}))
+	if err != ErrInexact || res.String() != "1844674407370955200" {
+		t.Errorf("2^64e-1 decoded as %s (%v)", res, err)
+	}
+	// exponent out of dec64 range but exact
+	res, err = ReadCBOR(bytes.NewReader([]byte{0xc4, 0x82, 0x38, 0x80, 0x18, 0x64}))
+	if err != nil || res != Dec64(1*256+(256-127)) {
+		t.Errorf("100e-129 decoded as %s (%v)", res, err)
+	}
+	if _, err = ReadCBOR(bytes.NewReader([]byte{0xc4, 0x82, 0x21})); err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated gives %v", err)
+	}
+	if _, err = ReadCBOR(bytes.NewReader([]byte{0xf9, 0x3c, 0x00})); err == nil {
+		t.Errorf("1.0 float should be an error")
+	}
+}
+
+func TestCBORList(t *testing.T) {
+	values := make([]Dec64, 100)
+	for i := range values {
+		values[i] = Dec64(int64(i*1000-50000)<<8 | int64(i%5))
+	}
+	values[3] = Empty
+	values[7] = NaN
+	var buf bytes.Buffer
+	if err := ListToCBOR(&buf, values); err != nil {
+		t.Errorf("ListToCBOR: %v", err)
+	}
+	res, err := ListFromCBOR(&buf)
+	if err != nil || len(res) != len(values) {
+		t.Errorf("ListFromCBOR gives %d values (%v)", len(res), err)
+		return
+	}
+	for i := range values {
+		if res[i] != values[i] {
+			t.Errorf("values[%d] is %s should be %s", i, res[i], values[i])
+		}
+	}
+	// indefinite array
+	res, err = ListFromCBOR(bytes.NewReader([]byte{0x9f, 0x01, 0xf6, 0xc4, 0x82, 0x20, 0x0f, 0xff}))
+	if err != nil || len(res) != 3 || res[0].String() != "1" || res[1] != Empty || res[2].String() != "1.5" {
+		t.Errorf("Indefinite array gives %v (%v)", res, err)
+	}
+	_, err = ListFromCBOR(bytes.NewReader([]byte{0x82, 0x01, 0x40}))
+	if err == nil || errors.Is(err, ErrInexact) {
+		t.Errorf("Byte string in list gives %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+// Package csv reads and writes columns of dec64 from encoding/csv
+// readers and writers, one row at a time.
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cedricjoulain/dec64"
+)
+
+// Column selects a CSV column and tells how values are written in it.
+type Column struct {
+	// Index of column in records, from 0, used when Name is empty.
+	Index int
+	// Name of column in header.
+	Name string
+	// Locale of values, nil for dec64.Parse and String.
+	Locale *dec64.Locale
+	// Notation decorations accepted with Locale.
+	Notation dec64.Notation
+	// Nulls tokens read as Empty in addition to the empty field,
+	// first one is written for Empty and NotAvailable.
+	Nulls []string
+}
+
+// parse returns value of field s.
+func (c *Column) parse(s string) (dec64.Dec64, error) {
+	if s == "" {
+		return dec64.Empty, nil
+	}
+	for _, n := range c.Nulls {
+		if s == n {
+			return dec64.Empty, nil
+		}
+	}
+	if strings.EqualFold(s, "NaN") {
+		return dec64.NaN, nil
+	}
+	if c.Locale == nil {
+		if c.Notation != 0 {
+			return dec64.ParseNotation(s, dec64.LocaleEN, c.Notation)
+		}
+		return dec64.Parse(s)
+	}
+	return dec64.ParseNotation(s, *c.Locale, c.Notation)
+}
+
+// format returns field of value d.
+func (c *Column) format(d dec64.Dec64) string {
+	switch {
+	case d == dec64.Empty || d == dec64.NotAvailable:
+		if len(c.Nulls) > 0 {
+			return c.Nulls[0]
+		}
+		return ""
+	case d.IsNaN():
+		return "NaN"
+	case c.Locale == nil && c.Notation == 0:
+		return d.String()
+	case c.Locale == nil:
+		return d.FormatNotation(dec64.LocaleEN, c.Notation, "")
+	}
+	return d.FormatNotation(*c.Locale, c.Notation, "")
+}
+
+// ParseError is returned for fields that are not dec64 values.
+type ParseError struct {
+	// Line number of record from 1, header included. It is the line
+	// in file unless quoted fields span several lines.
+	Line int
+	// Column index in record, from 0.
+	Column int
+	// Field text.
+	Field string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %q: %v", e.Line, e.Column, e.Field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingField record is too short for selected columns.
+var ErrMissingField = errors.New("Missing field")
+
+// Reader reads selected columns of a CSV reader.
+type Reader struct {
+	// Header tells that first record is a header,
+	// it is true when columns are selected by name.
+	Header bool
+
+	r       *stdcsv.Reader
+	columns []Column
+	line    int
+}
+
+// NewReader returns a reader of columns from r.
+func NewReader(r *stdcsv.Reader, columns ...Column) *Reader {
+	res := &Reader{r: r, columns: columns}
+	for _, c := range columns {
+		if c.Name != "" {
+			res.Header = true
+		}
+	}
+	return res
+}
+
+// readHeader reads first record and looks for column names.
+func (r *Reader) readHeader() error {
+	header, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	r.line++
+	for i := range r.columns {
+		c := &r.columns[i]
+		if c.Name == "" {
+			continue
+		}
+		c.Index = -1
+		for j, name := range header {
+			if name == c.Name {
+				c.Index = j
+				break
+			}
+		}
+		if c.Index < 0 {
+			return fmt.Errorf("Column %s not found in header", c.Name)
+		}
+	}
+	return nil
+}
+
+// Read reads next record into row, one value per selected column,
+// row is allocated when shorter. io.EOF is returned at end of input,
+// a *ParseError for a wrong field, reading may go on after it.
+func (r *Reader) Read(row []dec64.Dec64) ([]dec64.Dec64, error) {
+	if r.line == 0 && r.Header {
+		if err := r.readHeader(); err != nil {
+			return row, err
+		}
+	}
+	record, err := r.r.Read()
+	if err != nil {
+		return row, err
+	}
+	r.line++
+	if len(row) < len(r.columns) {
+		row = make([]dec64.Dec64, len(r.columns))
+	}
+	row = row[:len(r.columns)]
+	var perr error
+	for i := range r.columns {
+		c := &r.columns[i]
+		if c.Index >= len(record) {
+			row[i] = dec64.Empty
+			if perr == nil {
+				perr = &ParseError{Line: r.line, Column: c.Index, Err: ErrMissingField}
+			}
+			continue
+		}
+		var e error
+		row[i], e = c.parse(record[c.Index])
+		if e != nil && perr == nil {
+			perr = &ParseError{Line: r.line, Column: c.Index, Field: record[c.Index], Err: e}
+		}
+	}
+	return row, perr
+}
+
+// ReadAll reads all remaining records and returns one slice per
+// selected column. Reading stops on first error.
+func (r *Reader) ReadAll() (columns [][]dec64.Dec64, err error) {
+	columns = make([][]dec64.Dec64, len(r.columns))
+	var row []dec64.Dec64
+	for {
+		row, err = r.Read(row)
+		if err == io.EOF {
+			return columns, nil
+		}
+		if err != nil {
+			return
+		}
+		for i, d := range row {
+			columns[i] = append(columns[i], d)
+		}
+	}
+}
+
+// Writer writes rows of dec64 to a CSV writer.
+type Writer struct {
+	// Columns formats of written values by position,
+	// missing ones are written with String and empty nulls.
+	Columns []Column
+
+	w      *stdcsv.Writer
+	record []string
+}
+
+// NewWriter returns a writer to w, columns tell how values are written.
+func NewWriter(w *stdcsv.Writer, columns ...Column) *Writer {
+	return &Writer{Columns: columns, w: w}
+}
+
+// WriteHeader writes names as first record.
+func (w *Writer) WriteHeader(names ...string) error {
+	return w.w.Write(names)
+}
+
+// Write writes a row of values, output is buffered until Flush.
+func (w *Writer) Write(row []dec64.Dec64) error {
+	w.record = w.record[:0]
+	var c Column
+	for i, d := range row {
+		if i < len(w.Columns) {
+			c = w.Columns[i]
+		} else {
+			c = Column{}
+		}
+		w.record = append(w.record, c.format(d))
+	}
+	return w.w.Write(w.record)
+}
+
+// WriteAll writes columns of the same length row by row and flushes.
+func (w *Writer) WriteAll(columns ...[]dec64.Dec64) error {
+	n := 0
+	if len(columns) > 0 {
+		n = len(columns[0])
+	}
+	for _, c := range columns {
+		if len(c) != n {
+			return fmt.Errorf("Columns lengths %d and %d differ", n, len(c))
+		}
+	}
+	row := make([]dec64.Dec64, len(columns))
+	for i := 0; i < n; i++ {
+		for j, c := range columns {
+			row[j] = c[i]
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes buffered rows and returns underlying writer error.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,105 @@
+package csv
+
+import (
+	"bytes"
+	stdcsv "encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cedricjoulain/dec64"
+)
+
+const prices = `date;bid;ask;volume
+2024-01-02;1,0825;1,0827;1.250
+2024-01-03;N/A;1,0830;-
+2024-01-04;1,0831;1,0833;12
+`
+
+func TestReadAll(t *testing.T) {
+	r := stdcsv.NewReader(strings.NewReader(prices))
+	r.Comma = ';'
+	cr := NewReader(r,
+		Column{Name: "bid", Locale: &dec64.LocaleDE, Nulls: []string{"N/A"}},
+		Column{Name: "volume", Locale: &dec64.LocaleDE, Nulls: []string{"-"}},
+	)
+	columns, err := cr.ReadAll()
+	if err != nil || len(columns) != 2 || len(columns[0]) != 3 {
+		t.Errorf("ReadAll gives %v (%v)", columns, err)
+		return
+	}
+	ref := [][]string{{"1.0825", "null", "1.0831"}, {"1250", "null", "12"}}
+	for i, c := range columns {
+		for j, d := range c {
+			if d.String() != ref[i][j] {
+				t.Errorf("columns[%d][%d] is %s should be %s", i, j, d, ref[i][j])
+			}
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	input := "1.5,2\n3,x\n4\n"
+	cr := NewReader(stdcsv.NewReader(strings.NewReader(input)), Column{Index: 0}, Column{Index: 1})
+	// lines are read one by one and reading goes on after errors
+	row, err := cr.Read(nil)
+	if err != nil || row[0].String() != "1.5" || row[1].String() != "2" {
+		t.Errorf("Line 1 gives %v (%v)", row, err)
+	}
+	row, err = cr.Read(row)
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 || perr.Column != 1 || perr.Field != "x" {
+		t.Errorf("Line 2 gives %v", err)
+	}
+	if err != nil && err.Error() != `line 2, column 1: "x": Unable to parse dec64 from x` {
+		t.Errorf("Error message is %s", err)
+	}
+	// csv reader checks number of fields
+	cr.r.FieldsPerRecord = -1
+	_, err = cr.Read(row)
+	if !errors.As(err, &perr) || perr.Line != 3 || !errors.Is(err, ErrMissingField) {
+		t.Errorf("Line 3 gives %v", err)
+	}
+	if _, err = cr.Read(row); err != io.EOF {
+		t.Errorf("End gives %v", err)
+	}
+	// unknown column
+	cr = NewReader(stdcsv.NewReader(strings.NewReader(prices)), Column{Name: "mid"})
+	if _, err = cr.ReadAll(); err == nil {
+		t.Errorf("Unknown column should be an error")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(stdcsv.NewWriter(&buf),
+		Column{Locale: &dec64.LocaleFR, Nulls: []string{"NA"}},
+		Column{Notation: dec64.Percent})
+	a, _ := dec64.Parse("-1234.5")
+	b, _ := dec64.Parse("0.125")
+	c, _ := dec64.Parse("7")
+	if err := w.WriteHeader("value", "rate", "raw"); err != nil {
+		t.Errorf("WriteHeader: %v", err)
+	}
+	err := w.WriteAll([]dec64.Dec64{a, dec64.Empty}, []dec64.Dec64{b, dec64.NaN}, []dec64.Dec64{c, dec64.Empty})
+	if err != nil {
+		t.Errorf("WriteAll: %v", err)
+	}
+	ref := "value,rate,raw\n\"-1 234,5\",12.5%,7\nNA,NaN,\n"
+	if buf.String() != ref {
+		t.Errorf("Written %q should be %q", buf.String(), ref)
+	}
+	// read back
+	cr := NewReader(stdcsv.NewReader(&buf),
+		Column{Name: "value", Locale: &dec64.LocaleFR, Nulls: []string{"NA"}},
+		Column{Name: "rate", Notation: dec64.Percent})
+	columns, err := cr.ReadAll()
+	if err != nil || columns[0][0] != a || columns[0][1] != dec64.Empty ||
+		!columns[1][0].Equal(b) || !columns[1][1].IsNaN() {
+		t.Errorf("Read back %v (%v)", columns, err)
+	}
+	if err = w.WriteAll([]dec64.Dec64{a}, nil); err == nil {
+		t.Errorf("Different lengths should be an error")
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -15,30 +17,152 @@ import (
 // Dec64 decimal number representation.
 type Dec64 int64
 
+// New returns coef*10^exp, 0 for a zero coefficient whatever exp.
+// ErrRange is returned when coef does not fit in 56 bits or exp
+// is not in [-127, 127] (-128 is reserved for NaN).
+func New(coef int64, exp int) (Dec64, error) {
+	if coef == 0 {
+		return 0, nil
+	}
+	if coef > MaxCoefficient || coef < MinCoefficient || exp < -127 || exp > 127 {
+		return Empty, ErrRange
+	}
+	return word(coef, exp), nil
+}
+
+// pack returns coef*10^exp for a coef fitting in 56 bits, 0 for a zero
+// coefficient so that Empty or NotAvailable are never built by chance.
+// Out of range exponents are brought back scaling coef (half up),
+// NaN when too big as dec64.com does on overflow.
+func pack(coef int64, exp int) Dec64 {
+	if coef == 0 {
+		return 0
+	}
+	if exp >= -127 && exp <= 127 {
+		return word(coef, exp)
+	}
+	c := uint64(coef)
+	if coef < 0 {
+		c = -c
+	}
+	d, err := fit(coef < 0, c, exp, false, RoundHalfUp)
+	if err == ErrRange {
+		return NaN
+	}
+	return d
+}
+
+// word returns the dec64 encoding of coef and exp without any check,
+// zero coefficients keep exp so that specials round trip.
+func word(coef int64, exp int) Dec64 {
+	return Dec64(coef<<8 | int64(exp)&0xff)
+}
+
+// Coefficient returns the 56 bits signed coefficient of d.
+func (d Dec64) Coefficient() int64 {
+	return int64(d) >> 8
+}
+
+// Exponent returns the signed exponent of d, -128 for NaN.
+func (d Dec64) Exponent() int {
+	return int(int8(d))
+}
+
+// Digits returns number of decimal digits of coefficient, 0 for zero.
+func (d Dec64) Digits() (n int) {
+	for coef := d.Coefficient(); coef != 0; coef /= 10 {
+		n++
+	}
+	return
+}
+
 // ParseError input has a wron format.
 type ParseError error
 
+// ErrRange value is out of dec64 range.
+var ErrRange = errors.New("Value out of dec64 range")
+
+// ErrInexact digits were lost during a conversion,
+// returned along with the rounded value.
+var ErrInexact = errors.New("Inexact conversion")
+
+// ErrPrecision value has more digits than the target precision.
+var ErrPrecision = errors.New("Value exceeds precision")
+
+// Coefficient range, 56 bits signed.
+const (
+	MaxCoefficient = 0x007fffffffffffff
+	MinCoefficient = -0x0080000000000000
+)
+
 // Empty no value encoding.
 const Empty = Dec64(0x0000000000000001)
 
 // NotAvailable missing encoding.
 const NotAvailable = Dec64(0x00000000000000ff)
 
+// NaN not a number encoding, exponent -128 is reserved for it.
+const NaN = Dec64(0x0000000000000080)
+
 // Epsilon tolerance for comparaison with Float64.
 const Epsilon = 3e-13
 
+// errors completed with parsed text by Parse and ParseBytes
+var (
+	errSyntax   = errors.New("syntax")
+	errTooSmall = errors.New("too small")
+	errTooBig   = errors.New("too big")
+)
+
+// parseError returns error of parseBytes for text s.
+func parseError(err error, s string) error {
+	switch err {
+	case errSyntax:
+		return ParseError(fmt.Errorf("Unable to parse dec64 from %s", s))
+	case errTooSmall:
+		return ParseError(fmt.Errorf("%s is too small for dec64", s))
+	case errTooBig:
+		return ParseError(fmt.Errorf("%s is too big for dec64", s))
+	}
+	return err
+}
+
 // Parse returns a dec64 form string.
-func Parse(s string) (res Dec64, err error) {
+func Parse(s string) (Dec64, error) {
+	// small strings are converted on stack
+	res, err := parseBytes([]byte(s))
+	if err != nil {
+		err = parseError(err, s)
+	}
+	return res, err
+}
+
+// ParseBytes returns a dec64 from b as Parse does, without allocating.
+func ParseBytes(b []byte) (Dec64, error) {
+	res, err := parseBytes(b)
+	if err != nil {
+		err = parseError(err, string(b))
+	}
+	return res, err
+}
+
+// parseBytes parses s, errSyntax, errTooSmall and errTooBig
+// are returned without the text which must not escape.
+func parseBytes(s []byte) (res Dec64, err error) {
 	res = Empty
-	if len(s) == 0 || s == "null" {
+	if len(s) == 0 || string(s) == "null" {
 		return
 	}
 	start := 0
 	neg := false
-	for s[start] == ' ' {
+	for start < len(s) && s[start] == ' ' {
 		// trim starting space
 		start++
 	}
+	if start == len(s) {
+		err = errSyntax
+		return
+	}
 	if s[start] == '+' {
 		// just forget
 		start++
@@ -66,9 +190,9 @@ func Parse(s string) (res Dec64, err error) {
 				continue
 			}
 			if neg {
-				overflow = (coef * factor * 10) < (-1 * 0x7fffffffffffff)
+				overflow = (coef * factor * 10) < (-1 * MaxCoefficient)
 			} else {
-				overflow = (coef * factor * 10) > 0x7fffffffffffff
+				overflow = (coef * factor * 10) > MaxCoefficient
 			}
 			if !overflow {
 				factor *= 10
@@ -86,9 +210,9 @@ func Parse(s string) (res Dec64, err error) {
 			if neg {
 				ncoef = 10*coef - int64(s[i]-'0')
 				// check for overload
-				if ncoef < (-1 * 0x7fffffffffffff) {
+				if ncoef < (-1 * MaxCoefficient) {
 					// Can round up ?
-					if s[i] >= '5' && coef > (-1*0x7fffffffffffff) {
+					if s[i] >= '5' && coef > (-1*MaxCoefficient) {
 						coef--
 					}
 					if dot {
@@ -104,9 +228,9 @@ func Parse(s string) (res Dec64, err error) {
 			} else {
 				ncoef = 10*coef + int64(s[i]-'0')
 				// check for overload
-				if ncoef > 0x7fffffffffffff {
+				if ncoef > MaxCoefficient {
 					// Can round up ?
-					if s[i] >= '5' && coef != 0x7fffffffffffff {
+					if s[i] >= '5' && coef != MaxCoefficient {
 						coef++
 					}
 					if dot {
@@ -130,7 +254,7 @@ func Parse(s string) (res Dec64, err error) {
 			dot = true
 			continue
 		}
-		err = ParseError(fmt.Errorf("Unable to parse dec64 from %s", s))
+		err = errSyntax
 		return
 	}
 	// if early stop look if some exponent
@@ -164,15 +288,15 @@ func Parse(s string) (res Dec64, err error) {
 	exp += addExp
 	// -128 is kept for special values
 	if exp < -127 {
-		err = ParseError(fmt.Errorf("%s is too small for dec64", s))
+		err = errTooSmall
 		return
 	}
 	if exp > 127 {
-		err = ParseError(fmt.Errorf("%s is too big for dec64", s))
+		err = errTooBig
 		return
 	}
 
-	res = Dec64((coef << 8) | (exp & 0xff))
+	res = pack(coef, int(exp))
 	return
 }
 
@@ -183,8 +307,8 @@ func (d Dec64) String() string {
 	}
 	// normalize to avoid 1.0000 for example
 	d = Normalize(d)
-	exp := int8(d)
-	coef := int64(d) >> 8
+	exp := d.Exponent()
+	coef := d.Coefficient()
 	if coef == 0 {
 		return "0"
 	}
@@ -230,9 +354,9 @@ func (d Dec64) String() string {
 	if exp > 0 {
 		// Bigger
 		var z strings.Builder
-		z.Grow(int(exp) + len(chr))
+		z.Grow(exp + len(chr))
 		z.Write(chr)
-		for i := int8(0); i < exp; i++ {
+		for i := 0; i < exp; i++ {
 			z.WriteByte('0')
 		}
 		return z.String()
@@ -240,14 +364,17 @@ func (d Dec64) String() string {
 	return string(chr)
 }
 
-// MarshalJSON Dec64 as a decimal
+// MarshalJSON Dec64 as a decimal, Empty, NotAvailable and NaN are null.
 func (d Dec64) MarshalJSON() ([]byte, error) {
+	if d == Empty || d == NotAvailable || d.IsNaN() {
+		return []byte("null"), nil
+	}
 	return []byte(d.String()), nil
 }
 
-// UnmarshalJSON Dec64 as a decimal
+// UnmarshalJSON Dec64 from a decimal or a quoted decimal.
 func (d *Dec64) UnmarshalJSON(data []byte) (err error) {
-	*d, err = Parse(string(data))
+	*d, err = unmarshalJSON(data, reflect.TypeOf(*d))
 	return
 }
 
@@ -279,10 +406,10 @@ func Float64(d Dec64) (f float64) {
 	if d == Empty {
 		return math.NaN()
 	}
-	if d&0xff > 127 {
-		return float64(int64(d)>>8) / Expf[256-d&0xff]
+	if e := d.Exponent(); e < 0 {
+		return float64(d.Coefficient()) / Expf[-e]
 	}
-	return float64(int64(d)>>8) * Expf[d&0xff]
+	return float64(d.Coefficient()) * Expf[d.Exponent()]
 }
 
 // FromFloat64 converts float64 to Dec64.
@@ -301,29 +428,26 @@ func FromInt64(i int64) (Dec64, error) {
 
 // Int64 converts Dec64 to "normal" int64 keeping sign
 func Int64(d Dec64) int64 {
-	mant := int64(d) >> 8
-	exp := int64(d) & 0xff
-	if exp > 127 {
-		return mant / Expi[exp]
+	mant := d.Coefficient()
+	exp := d.Exponent()
+	if exp < 0 {
+		return mant / Expi[-exp]
 	}
 	return mant * Expi[exp]
 }
 
 // Normalize Dec64 -> mantisse % 10 != 0
 func Normalize(d Dec64) Dec64 {
-	mant := int64(d) >> 8
+	mant := d.Coefficient()
 	if mant == 0 {
 		return 0
 	}
-	exp := int64(d) & 0xff
-	if exp > 127 {
-		exp -= 256
-	}
+	exp := d.Exponent()
 	for mant%10 == 0 {
 		mant /= 10
 		exp++
 	}
-	return Dec64(mant<<8 | (exp & 0xff))
+	return pack(mant, exp)
 }
 
 // Equal compares 2 dec64, empty and not available are every thing.
@@ -347,59 +471,90 @@ func (d *Dec64) Equal(b Dec64) bool {
 // Dec64 will probably no longer be normalized
 func Homogenize(values []Dec64) {
 	// First find smaller exponent
-	exp := int64(127)
-	for _, d := range values {
-		if d == 0 {
-			// forget 0
-			continue
-		}
-		e := int64(d) & 0xff
-		if e > 127 {
-			// negative
-			e -= 256
-		}
-		if exp > e {
-			exp = e
-		}
-	}
+	exp := minExponent(values)
 	// modify !
 	for i, d := range values {
 		if d == 0 {
 			// forget 0
 			continue
 		}
-		e := int64(d) & 0xff
-		if e > 127 {
-			// negative
-			e -= 256
-		}
 		// we need to multiply mantisse by 10^exp
-		e = e - exp
+		e := int64(d.Exponent()) - exp
 		if e <= 0 || e > 18 {
 			// same or to huge difference, nothing to do
 			continue
 		}
-		old := int64(d) >> 8
-		mant := (old * Expi[e]) & 0xffffffffffffff
-		// simple overflow check TODO optimize?
+		old := d.Coefficient()
+		mant := old * Expi[e]
 		if mant/Expi[e] != old {
 			// do nothing
 			continue
 		}
-		// do not set to zero when undefined
-		if mant != 0 || (exp&0xff) != 0 {
-			values[i] = Dec64(mant<<8 | (exp & 0xff))
+		// do not set to zero when undefined, nor out of 56 bits
+		if v, err := New(mant, int(exp)); err == nil && mant != 0 {
+			values[i] = v
+		}
+	}
+}
+
+// minExponent returns smaller exponent of non zero values, 127 if none.
+func minExponent(values []Dec64) int64 {
+	exp := int64(127)
+	for _, d := range values {
+		if d == 0 {
+			// forget 0
+			continue
+		}
+		e := int64(d.Exponent())
+		if exp > e {
+			exp = e
+		}
+	}
+	return exp
+}
+
+// numbers returns values without zeros, Empty, NotAvailable and NaN
+// that would change common exponent.
+func numbers(values []Dec64) []Dec64 {
+	res := make([]Dec64, 0, len(values))
+	for _, d := range values {
+		if d.Coefficient() != 0 && !isSpecial(d) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// convertAll calls convert for values 0 to n-1, or until it returns io.EOF
+// when n is negative, and returns the number of values converted.
+// Conversion stops on first error but ErrInexact which is returned
+// for the first rounded value once all values are converted.
+func convertAll(n int, convert func(i int) error) (int, error) {
+	var inexact error
+	i := 0
+	for ; n < 0 || i < n; i++ {
+		err := convert(i)
+		if err == io.EOF && n < 0 {
+			break
+		}
+		if err != nil && err != ErrInexact {
+			return i, fmt.Errorf("values[%d]: %w", i, err)
+		}
+		if err != nil && inexact == nil {
+			inexact = fmt.Errorf("values[%d]: %w", i, err)
 		}
 	}
+	return i, inexact
+}
+
+// IsNaN checks that d is a not a number encoding.
+func (d Dec64) IsNaN() bool {
+	return d.Exponent() == -128
 }
 
 // IsInt checks that d is an integer with no decimal parts.
 func (d Dec64) IsInt() bool {
 	// Normalize to ensure exponant is fully significativ
-	e := int64(Normalize(d)) & 0xff
-	if e > 127 {
-		// negative, we have a decimal part
-		return false
-	}
-	return true
+	// negative, we have a decimal part
+	return Normalize(d).Exponent() >= 0
 }
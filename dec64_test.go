@@ -3,7 +3,6 @@ package dec64
 import (
 	"bytes"
 	"fmt"
-	"io"
 	"math"
 	"strconv"
 	"testing"
@@ -431,10 +430,8 @@ func TestListRW(t *testing.T) {
 	}
 	read, err := ListFromReader(&buf)
 	if err != nil {
-		if err != io.EOF {
-			t.Error(err)
-			return
-		}
+		t.Error(err)
+		return
 	}
 	if len(read) != len(values) {
 		t.Errorf("len is %d should be %d", len(read), len(values))
@@ -587,3 +584,114 @@ func testBug(t *testing.T) {
 	ref = a
 	testAdd(t, a, b, ref)
 }
+
+func testNew(t *testing.T, coef int64, exp int, ref string, referr error) {
+	d, err := New(coef, exp)
+	if err != referr {
+		t.Errorf("New(%d, %d) error is %v should be %v", coef, exp, err, referr)
+		return
+	}
+	if err == nil && d.String() != ref {
+		t.Errorf("New(%d, %d) is %s should be %s", coef, exp, d, ref)
+	}
+}
+
+func TestNew(t *testing.T) {
+	testNew(t, 12345, -2, "123.45", nil)
+	testNew(t, -7, 3, "-7000", nil)
+	testNew(t, MaxCoefficient, 0, "36028797018963967", nil)
+	testNew(t, MinCoefficient, 0, "-36028797018963968", nil)
+	testNew(t, MaxCoefficient+1, 0, "", ErrRange)
+	testNew(t, MinCoefficient-1, 0, "", ErrRange)
+	testNew(t, 1, 128, "", ErrRange)
+	testNew(t, 1, -128, "", ErrRange)
+	// zero is never a special
+	for _, exp := range []int{-128, -1, 0, 1, 200} {
+		if d, err := New(0, exp); d != 0 || err != nil {
+			t.Errorf("New(0, %d) is %x (%v) should be 0", exp, int64(d), err)
+		}
+	}
+}
+
+func TestPackRange(t *testing.T) {
+	// exponent overflow is NaN
+	a := Dec64(MaxCoefficient*256 + 127)
+	if r := a.Add(a); !r.IsNaN() {
+		t.Errorf("Max+Max is %x should be NaN", int64(r))
+	}
+	if r := a.Neg().Add(a.Neg()); !r.IsNaN() {
+		t.Errorf("-Max-Max is %x should be NaN", int64(r))
+	}
+	// exponent brought back by scaling coefficient
+	if d := pack(5, 128); d.String() != pack(50, 127).String() {
+		t.Errorf("5e128 is %s", d)
+	}
+	if d := pack(15, -128); d != pack(2, -127) {
+		t.Errorf("15e-128 is %x", int64(d))
+	}
+	// sum to zero is not NotAvailable
+	b := Dec64(5*256 + 255)
+	if r := b.Add(b.Neg()); r != 0 {
+		t.Errorf("0.5-0.5 is %x should be 0", int64(r))
+	}
+	for _, d := range []Dec64{Empty, NotAvailable, NaN, 0} {
+		if d.Neg() != d {
+			t.Errorf("Neg of %x is %x", int64(d), int64(d.Neg()))
+		}
+	}
+	// negative values are homogenized too
+	values := []Dec64{Dec64(-15*256 + 255), Dec64(2 * 256), Dec64(-3 * 256)}
+	Homogenize(values)
+	for i, ref := range []Dec64{Dec64(-15*256 + 255), Dec64(20*256 + 255), Dec64(-30*256 + 255)} {
+		if values[i] != ref {
+			t.Errorf("Homogenize values[%d] is %s (%x) should be %x", i, values[i], int64(values[i]), int64(ref))
+		}
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	d := Dec64(-1234500*256 + 256 - 4)
+	if d.Coefficient() != -1234500 || d.Exponent() != -4 || d.Digits() != 7 {
+		t.Errorf("%s gives %d %d %d", d, d.Coefficient(), d.Exponent(), d.Digits())
+	}
+	if Empty.Coefficient() != 0 || Empty.Exponent() != 1 || Empty.Digits() != 0 {
+		t.Errorf("Empty gives %d %d %d", Empty.Coefficient(), Empty.Exponent(), Empty.Digits())
+	}
+	if NaN.Exponent() != -128 {
+		t.Errorf("NaN exponent is %d should be -128", NaN.Exponent())
+	}
+}
+
+func TestMult(t *testing.T) {
+	a, _ := Parse("1.5")
+	b, _ := Parse("-4")
+	if r := a.Mult(b); !r.Equal(Dec64(-6 * 256)) {
+		t.Errorf("1.5*-4 is %s should be -6", r)
+	}
+	max := Dec64(MaxCoefficient * 256)
+	// too many digits are rounded, Max^2 is 1298074214633706835075030044377089
+	if r := max.Mult(Dec64(10 * 256)); r.String() != "360287970189639670" {
+		t.Errorf("Max*10 is %s", r)
+	}
+	if r := max.Mult(max.Neg()); r != pack(-12980742146337068, 17) {
+		t.Errorf("Max*-Max is %s", r)
+	}
+	// too big is NaN
+	big := Dec64(1*256 + 127)
+	if r := big.Mult(big); !r.IsNaN() {
+		t.Errorf("1e127*1e127 is %s", r)
+	}
+	if r := big.MultInt64(-1 << 62); !r.IsNaN() {
+		t.Errorf("1e127*-2^62 is %s", r)
+	}
+	if r := a.Mult(0); r != 0 {
+		t.Errorf("1.5*0 is %x", int64(r))
+	}
+	if r := a.Mult(Empty); r != Empty {
+		t.Errorf("1.5*Empty is %x", int64(r))
+	}
+	e := Empty
+	if r := e.MultInt64(3); r != Empty {
+		t.Errorf("Empty*3 is %x", int64(r))
+	}
+}
@@ -0,0 +1,166 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Arrow decimal128 and Parquet DECIMAL physical layouts.
+// Values are integers v with implied decimals, d is v*10^-scale,
+// and must have at most precision digits.
+
+// ArrowDecimal128Size bytes of a decimal128 value.
+const ArrowDecimal128Size = 16
+
+// DecimalScale returns the scale needed to write all values exactly,
+// from the smaller exponent as Homogenize would choose,
+// 0 for integers. Empty, NotAvailable and NaN are ignored.
+func DecimalScale(values []Dec64) int {
+	if exp := minExponent(numbers(values)); exp < 0 {
+		return int(-exp)
+	}
+	return 0
+}
+
+// ParquetFixedLen returns minimal FIXED_LEN_BYTE_ARRAY length
+// for a DECIMAL of precision digits.
+func ParquetFixedLen(precision int) int {
+	max := new(big.Int).Sub(pow10(precision), big.NewInt(1))
+	// sign bit
+	return (max.BitLen() + 1 + 7) / 8
+}
+
+// putTwos writes v in big endian two's complement filling b.
+func putTwos(b []byte, v *big.Int) {
+	if v.Sign() >= 0 {
+		v.FillBytes(b)
+		return
+	}
+	u := new(big.Int).Lsh(big.NewInt(1), uint(8*len(b)))
+	u.Add(u, v).FillBytes(b)
+}
+
+// twos returns integer written in big endian two's complement in b.
+func twos(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return v
+}
+
+// reverse b in place, from big to little endian.
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// encodeDecimals writes values in size bytes two's complement, little endian if le.
+func encodeDecimals(values []Dec64, precision, scale int, mode RoundingMode, size int, le bool) ([]byte, error) {
+	buf := make([]byte, size*len(values))
+	limit := pow10(precision)
+	n, err := convertAll(len(values), func(i int) error {
+		v, err := ToScaledBigInt(values[i], scale, mode)
+		if err != nil && err != ErrInexact {
+			return err
+		}
+		if new(big.Int).Abs(v).Cmp(limit) >= 0 {
+			return ErrPrecision
+		}
+		b := buf[i*size : (i+1)*size]
+		putTwos(b, v)
+		if le {
+			reverse(b)
+		}
+		return err
+	})
+	return buf[:n*size], err
+}
+
+// decodeDecimals reads values of size bytes two's complement, little endian if le.
+func decodeDecimals(buf []byte, scale, size int, le bool) ([]Dec64, error) {
+	if len(buf)%size != 0 {
+		return nil, fmt.Errorf("Buffer length %d is not a multiple of %d", len(buf), size)
+	}
+	res := make([]Dec64, len(buf)/size)
+	b := make([]byte, size)
+	n, err := convertAll(len(res), func(i int) (err error) {
+		copy(b, buf[i*size:])
+		if le {
+			reverse(b)
+		}
+		res[i], err = fromBigExact(twos(b), -scale)
+		return
+	})
+	return res[:n], err
+}
+
+// checkPrecision returns an error when precision is not in [1, max].
+func checkPrecision(precision, max int) error {
+	if precision < 1 || precision > max {
+		return fmt.Errorf("Precision %d is not in [1, %d]", precision, max)
+	}
+	return nil
+}
+
+// ArrowDecimal128 returns values as an Arrow decimal128(precision, scale)
+// data buffer, 16 bytes little endian each, rounded with mode.
+// Conversion stops on first error but ErrInexact which is reported
+// for the first rounded value once all values are converted.
+// Errors wrap ErrRange for Empty, NotAvailable and NaN, ErrPrecision
+// for values with more than precision digits, use errors.Is.
+func ArrowDecimal128(values []Dec64, precision, scale int, mode RoundingMode) ([]byte, error) {
+	if err := checkPrecision(precision, 38); err != nil {
+		return nil, err
+	}
+	return encodeDecimals(values, precision, scale, mode, ArrowDecimal128Size, true)
+}
+
+// FromArrowDecimal128 returns values of an Arrow decimal128 data buffer
+// with scale. Errors wrap ErrInexact when a value has too many digits,
+// use errors.Is.
+func FromArrowDecimal128(buf []byte, scale int) ([]Dec64, error) {
+	return decodeDecimals(buf, scale, ArrowDecimal128Size, true)
+}
+
+// ParquetInt64 returns values as a Parquet DECIMAL(precision, scale)
+// INT64 column, 8 bytes little endian each, as ArrowDecimal128 does.
+func ParquetInt64(values []Dec64, precision, scale int, mode RoundingMode) ([]byte, error) {
+	if err := checkPrecision(precision, 18); err != nil {
+		return nil, err
+	}
+	return encodeDecimals(values, precision, scale, mode, 8, true)
+}
+
+// FromParquetInt64 returns values of a Parquet DECIMAL INT64 column.
+func FromParquetInt64(buf []byte, scale int) ([]Dec64, error) {
+	if len(buf)%8 != 0 {
+		return nil, fmt.Errorf("Buffer length %d is not a multiple of 8", len(buf))
+	}
+	ints := make([]int64, len(buf)/8)
+	for i := range ints {
+		ints[i] = int64(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return FromScaledInts(ints, scale)
+}
+
+// ParquetFixed returns values as a Parquet DECIMAL(precision, scale)
+// FIXED_LEN_BYTE_ARRAY column, ParquetFixedLen(precision) bytes
+// big endian each, as ArrowDecimal128 does.
+func ParquetFixed(values []Dec64, precision, scale int, mode RoundingMode) ([]byte, error) {
+	if err := checkPrecision(precision, 76); err != nil {
+		return nil, err
+	}
+	return encodeDecimals(values, precision, scale, mode, ParquetFixedLen(precision), false)
+}
+
+// FromParquetFixed returns values of a Parquet DECIMAL FIXED_LEN_BYTE_ARRAY
+// column written with ParquetFixedLen(precision) bytes each.
+func FromParquetFixed(buf []byte, precision, scale int) ([]Dec64, error) {
+	if err := checkPrecision(precision, 76); err != nil {
+		return nil, err
+	}
+	return decodeDecimals(buf, scale, ParquetFixedLen(precision), false)
+}
@@ -0,0 +1,116 @@
+package dec64
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func parseAll(strs ...string) []Dec64 {
+	values := make([]Dec64, len(strs))
+	for i, s := range strs {
+		values[i], _ = Parse(s)
+	}
+	return values
+}
+
+func TestDecimalScale(t *testing.T) {
+	if s := DecimalScale(parseAll("1.5", "-2.125", "100", "", "0")); s != 3 {
+		t.Errorf("Scale is %d should be 3", s)
+	}
+	if s := DecimalScale(parseAll("1e3", "7")); s != 0 {
+		t.Errorf("Scale of integers is %d should be 0", s)
+	}
+	if s := DecimalScale([]Dec64{NaN, NotAvailable}); s != 0 {
+		t.Errorf("Scale of specials is %d should be 0", s)
+	}
+}
+
+func TestParquetFixedLen(t *testing.T) {
+	// from Parquet specification: 4 bytes up to 9 digits, 8 up to 18
+	for p, ref := range map[int]int{1: 1, 2: 1, 3: 2, 9: 4, 10: 5, 18: 8, 19: 9, 38: 16} {
+		if n := ParquetFixedLen(p); n != ref {
+			t.Errorf("Length for precision %d is %d should be %d", p, n, ref)
+		}
+	}
+}
+
+func TestArrowDecimal128(t *testing.T) {
+	values := parseAll("1.5", "-2.125", "0", "36028797018963967")
+	buf, err := ArrowDecimal128(values, 38, 3, RoundHalfEven)
+	if err != nil || len(buf) != 64 {
+		t.Errorf("ArrowDecimal128 gives %d bytes (%v)", len(buf), err)
+		return
+	}
+	// -2125 little endian
+	ref := []byte{0xb3, 0xf7, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if !bytes.Equal(buf[16:32], ref) {
+		t.Errorf("-2.125 is %x should be %x", buf[16:32], ref)
+	}
+	res, err := FromArrowDecimal128(buf, 3)
+	if err != nil || len(res) != len(values) {
+		t.Errorf("FromArrowDecimal128 gives %v (%v)", res, err)
+		return
+	}
+	for i := range values {
+		if !res[i].Equal(values[i]) {
+			t.Errorf("values[%d] is %s should be %s", i, res[i], values[i])
+		}
+	}
+	// precision too small
+	_, err = ArrowDecimal128(parseAll("1.5", "123.4"), 4, 2, RoundHalfEven)
+	if !errors.Is(err, ErrPrecision) || err.Error() != "values[1]: Value exceeds precision" {
+		t.Errorf("123.40 in decimal(4, 2) gives %v", err)
+	}
+	// rounding
+	buf, err = ArrowDecimal128(parseAll("0.125", "1"), 10, 2, RoundHalfEven)
+	if !errors.Is(err, ErrInexact) || buf[0] != 12 || buf[16] != 100 {
+		t.Errorf("0.125 in decimal(10, 2) gives %x (%v)", buf, err)
+	}
+	if _, err = ArrowDecimal128([]Dec64{Empty}, 10, 2, RoundHalfEven); !errors.Is(err, ErrRange) {
+		t.Errorf("Empty gives %v", err)
+	}
+	// zero at any scale
+	if res, err = FromArrowDecimal128(make([]byte, 16), 1); err != nil || res[0] != 0 {
+		t.Errorf("0 with scale 1 gives %#x (%v)", int64(res[0]), err)
+	}
+	// more than 17 digits
+	buf = make([]byte, 16)
+	buf[8] = 1
+	res, err = FromArrowDecimal128(buf, 0)
+	if !errors.Is(err, ErrInexact) || res[0].String() != "18446744073709552000" {
+		t.Errorf("2^64 gives %s (%v)", res[0], err)
+	}
+}
+
+func TestParquet(t *testing.T) {
+	values := parseAll("101.25", "-0.01", "99999.99")
+	buf, err := ParquetInt64(values, 7, 2, RoundHalfUp)
+	if err != nil || len(buf) != 24 || buf[0] != 0x8d || buf[1] != 0x27 {
+		t.Errorf("ParquetInt64 gives %x (%v)", buf, err)
+	}
+	res, err := FromParquetInt64(buf, 2)
+	if err != nil || res[0].String() != "101.25" || res[1].String() != "-0.01" || res[2].String() != "99999.99" {
+		t.Errorf("FromParquetInt64 gives %v (%v)", res, err)
+	}
+	// 7 digits fit in 4 bytes big endian
+	buf, err = ParquetFixed(values, 7, 2, RoundHalfUp)
+	ref := []byte{0, 0, 0x27, 0x8d, 0xff, 0xff, 0xff, 0xff, 0x00, 0x98, 0x96, 0x7f}
+	if err != nil || !bytes.Equal(buf, ref) {
+		t.Errorf("ParquetFixed gives %x (%v) should be %x", buf, err, ref)
+	}
+	res, err = FromParquetFixed(buf, 7, 2)
+	if err != nil || res[0].String() != "101.25" || res[1].String() != "-0.01" || res[2].String() != "99999.99" {
+		t.Errorf("FromParquetFixed gives %v (%v)", res, err)
+	}
+	if res, err = FromParquetInt64(make([]byte, 8), 1); err != nil || res[0] != 0 {
+		t.Errorf("0 with scale 1 gives %#x (%v)", int64(res[0]), err)
+	}
+	if _, err = ParquetInt64(values, 19, 2, RoundHalfUp); err == nil {
+		t.Errorf("Precision 19 should be an error for INT64")
+	}
+	if _, err = FromParquetFixed(buf[:5], 7, 2); err == nil {
+		t.Errorf("Truncated buffer should be an error")
+	}
+}
@@ -0,0 +1,170 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// DeltaBlockSize maximum number of values in a block of AppendDelta.
+const DeltaBlockSize = 256
+
+// delta block modes
+const (
+	// deltaRaw values as 8 bytes little endian words
+	deltaRaw = iota
+	// deltaVarint exponent byte then zigzag varint coefficient deltas
+	deltaVarint
+	// deltaNormalized as deltaVarint with values normalized once decoded
+	deltaNormalized
+)
+
+// errDeltaTruncated input ends inside a block.
+var errDeltaTruncated = errors.New("Truncated delta block")
+
+// AppendDelta appends values to b compressed by blocks of DeltaBlockSize.
+// Block exponents are homogenized and coefficient deltas written as zigzag
+// varints, blocks with specials, exponents too far apart or values that
+// could not be decoded exactly, as is or normalized, are written raw.
+func AppendDelta(b []byte, values []Dec64) []byte {
+	coefs := make([]int64, 0, DeltaBlockSize)
+	for len(values) > 0 {
+		n := len(values)
+		if n > DeltaBlockSize {
+			n = DeltaBlockSize
+		}
+		block := values[:n]
+		values = values[n:]
+		start := len(b)
+		var (
+			exp int64
+			ok  bool
+		)
+		coefs, exp, ok = homogenizeBlock(coefs[:0], block)
+		if mode := deltaMode(block, coefs, int(exp)); ok && mode != deltaRaw {
+			b = append(b, mode, byte(n-1), byte(exp))
+			prev := int64(0)
+			for _, c := range coefs {
+				b = appendVarint(b, c-prev)
+				prev = c
+			}
+			if len(b)-start <= 2+8*n {
+				continue
+			}
+			// raw is smaller
+			b = b[:start]
+		}
+		b = append(b, deltaRaw, byte(n-1))
+		for _, d := range block {
+			b = append(b, 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint64(b[len(b)-8:], uint64(d))
+		}
+	}
+	return b
+}
+
+// appendVarint appends zigzag varint of v.
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// homogenizeBlock appends to coefs coefficients of values with
+// the smaller exponent, ok is false when a value is special
+// or can not be written with it.
+func homogenizeBlock(coefs []int64, values []Dec64) (_ []int64, exp int64, ok bool) {
+	for _, d := range values {
+		if isSpecial(d) {
+			return coefs, 0, false
+		}
+	}
+	exp = minExponent(values)
+	for _, d := range values {
+		if d == 0 {
+			coefs = append(coefs, 0)
+			continue
+		}
+		// we need to multiply mantisse by 10^e
+		e := int64(d.Exponent()) - exp
+		if e > 18 {
+			return coefs, 0, false
+		}
+		old := d.Coefficient()
+		mant := old * Expi[e]
+		if mant/Expi[e] != old || mant > MaxCoefficient || mant < MinCoefficient {
+			return coefs, 0, false
+		}
+		coefs = append(coefs, mant)
+	}
+	return coefs, exp, true
+}
+
+// deltaMode returns block mode decoding coefs with exp exactly as values,
+// deltaRaw when none does.
+func deltaMode(values []Dec64, coefs []int64, exp int) byte {
+	if len(coefs) != len(values) {
+		return deltaRaw
+	}
+	same, normalized := true, true
+	for i, d := range values {
+		v := pack(coefs[i], exp)
+		same = same && v == d
+		normalized = normalized && Normalize(v) == d
+	}
+	switch {
+	case same:
+		return deltaVarint
+	case normalized:
+		return deltaNormalized
+	}
+	return deltaRaw
+}
+
+// DecodeDelta returns values written by AppendDelta.
+func DecodeDelta(b []byte) (values []Dec64, err error) {
+	values = make([]Dec64, 0, DeltaBlockSize)
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return values, errDeltaTruncated
+		}
+		mode, n := b[0], int(b[1])+1
+		b = b[2:]
+		switch mode {
+		case deltaRaw:
+			if len(b) < 8*n {
+				return values, errDeltaTruncated
+			}
+			for i := 0; i < n; i++ {
+				values = append(values, Dec64(binary.LittleEndian.Uint64(b[8*i:])))
+			}
+			b = b[8*n:]
+		case deltaVarint, deltaNormalized:
+			if len(b) < 1 {
+				return values, errDeltaTruncated
+			}
+			exp := int(int8(b[0]))
+			b = b[1:]
+			coef := int64(0)
+			for i := 0; i < n; i++ {
+				delta, size := binary.Varint(b)
+				if size <= 0 {
+					return values, errDeltaTruncated
+				}
+				b = b[size:]
+				coef += delta
+				if coef > MaxCoefficient || coef < MinCoefficient {
+					return values, ErrRange
+				}
+				d := pack(coef, exp)
+				if mode == deltaNormalized {
+					d = Normalize(d)
+				}
+				values = append(values, d)
+			}
+		default:
+			return values, fmt.Errorf("Unknown delta block mode %d", mode)
+		}
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package dec64
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// ticks returns a random walk of prices with 5 decimals.
+func ticks(n int) []Dec64 {
+	r := rand.New(rand.NewSource(42))
+	values := make([]Dec64, n)
+	coef := int64(108250)
+	for i := range values {
+		coef += int64(r.Intn(7) - 3)
+		values[i] = Normalize(pack(coef, -5))
+	}
+	return values
+}
+
+func testDelta(t *testing.T, values []Dec64) []byte {
+	b := AppendDelta(nil, values)
+	res, err := DecodeDelta(b)
+	if err != nil || len(res) != len(values) {
+		t.Errorf("DecodeDelta gives %d values (%v) should be %d", len(res), err, len(values))
+		return b
+	}
+	for i, d := range values {
+		if res[i] != d {
+			t.Errorf("values[%d] is %#x should be %#x", i, int64(res[i]), int64(d))
+		}
+	}
+	return b
+}
+
+func TestDelta(t *testing.T) {
+	values := ticks(1000)
+	b := testDelta(t, values)
+	if len(b) > 2*len(values) {
+		t.Errorf("%d values take %d bytes", len(values), len(b))
+	}
+	testDelta(t, nil)
+	testDelta(t, []Dec64{0})
+	testDelta(t, []Dec64{0, Dec64(15*256 + 255), Dec64(-3 * 256)})
+	// zeros next to exponents -1 and 1
+	testDelta(t, []Dec64{0, Dec64(5*256 + 255)})
+	testDelta(t, []Dec64{0, Dec64(1*256 + 1)})
+	// exponents of values are kept
+	for _, c := range []struct {
+		values []Dec64
+		mode   byte
+	}{
+		{[]Dec64{Dec64(20*256 + 255), Dec64(15*256 + 255)}, deltaVarint},
+		{[]Dec64{Dec64(2 * 256), Dec64(15*256 + 255)}, deltaNormalized},
+		{[]Dec64{Dec64(2 * 256), Dec64(20*256 + 255)}, deltaRaw},
+	} {
+		if b = testDelta(t, c.values); b[0] != c.mode {
+			t.Errorf("%v is written in mode %d should be %d", c.values, b[0], c.mode)
+		}
+	}
+	// specials make a raw block
+	values[300] = Empty
+	values[301] = NaN
+	values[302] = NotAvailable
+	b = testDelta(t, values)
+	if b[0] != deltaNormalized {
+		t.Errorf("First block should be normalized")
+	}
+	// exponents too far apart
+	testDelta(t, []Dec64{Dec64(MaxCoefficient * 256), Dec64(1*256 + 256 - 100)})
+	// raw block
+	b = AppendDelta(nil, []Dec64{Empty})
+	if len(b) != 10 || b[0] != deltaRaw {
+		t.Errorf("Empty is written as %x", b)
+	}
+	if _, err := DecodeDelta(b[:5]); err == nil {
+		t.Errorf("Truncated block should be an error")
+	}
+	if _, err := DecodeDelta([]byte{9, 0}); err == nil {
+		t.Errorf("Unknown mode should be an error")
+	}
+}
+
+func BenchmarkAppendDelta(b *testing.B) {
+	values := ticks(100000)
+	var buf []byte
+	b.SetBytes(int64(8 * len(values)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = AppendDelta(buf[:0], values)
+	}
+	b.ReportMetric(float64(8*len(values))/float64(len(buf)), "ratio")
+}
+
+func BenchmarkDecodeDelta(b *testing.B) {
+	values := ticks(100000)
+	buf := AppendDelta(nil, values)
+	b.SetBytes(int64(8 * len(values)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeDelta(buf); err != nil {
+			b.Error(err)
+			return
+		}
+	}
+	b.ReportMetric(float64(8*len(values))/float64(len(buf)), "ratio")
+}
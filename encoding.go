@@ -0,0 +1,118 @@
+package dec64
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// MarshalText Dec64 as a decimal, Empty is an empty text,
+// NotAvailable and NaN are NaN.
+func (d Dec64) MarshalText() ([]byte, error) {
+	switch {
+	case d == Empty:
+		return []byte{}, nil
+	case d == NotAvailable || d.IsNaN():
+		return []byte("NaN"), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText Dec64 from a decimal or NaN, empty text is Empty.
+func (d *Dec64) UnmarshalText(text []byte) (err error) {
+	if bytes.EqualFold(text, []byte("NaN")) {
+		*d = NaN
+		return
+	}
+	*d, err = ParseBytes(text)
+	return
+}
+
+// QuotedDec64 is a Dec64 written as a JSON string like "12.5",
+// as many exchange APIs do. Both quoted and unquoted numbers are read.
+type QuotedDec64 Dec64
+
+// MarshalJSON QuotedDec64 as a string, Empty, NotAvailable and NaN are null.
+func (q QuotedDec64) MarshalJSON() ([]byte, error) {
+	d := Dec64(q)
+	if d == Empty || d == NotAvailable || d.IsNaN() {
+		return d.MarshalJSON()
+	}
+	s := d.String()
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON QuotedDec64 from a string or a number.
+func (q *QuotedDec64) UnmarshalJSON(data []byte) error {
+	d, err := unmarshalJSON(data, reflect.TypeOf(*q))
+	if err != nil {
+		return err
+	}
+	*q = QuotedDec64(d)
+	return nil
+}
+
+// unmarshalJSON reads a JSON number, string or null as a dec64,
+// other JSON values are reported as an UnmarshalTypeError for typ.
+func unmarshalJSON(data []byte, typ reflect.Type) (Dec64, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Empty, nil
+	}
+	switch data[0] {
+	case 't', 'f':
+		return Empty, &json.UnmarshalTypeError{Value: "bool", Type: typ}
+	case '{':
+		return Empty, &json.UnmarshalTypeError{Value: "object", Type: typ}
+	case '[':
+		return Empty, &json.UnmarshalTypeError{Value: "array", Type: typ}
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return Empty, err
+		}
+		if strings.EqualFold(s, "NaN") {
+			return NaN, nil
+		}
+		d, err := Parse(s)
+		if err != nil {
+			return Empty, &json.UnmarshalTypeError{Value: "string " + string(data), Type: typ}
+		}
+		return d, nil
+	}
+	return Parse(string(data))
+}
+
+// Dec64 returns q as a Dec64.
+func (q QuotedDec64) Dec64() Dec64 {
+	return Dec64(q)
+}
+
+// JSONCodec writes and reads dec64 in JSON with NotAvailable and NaN
+// spelled as the string NaN, for instance "NaN" or "N/A".
+type JSONCodec struct {
+	// NaN spelling, null when empty.
+	NaN string
+}
+
+// Marshal d as Dec64.MarshalJSON does, NotAvailable and NaN are
+// the quoted c.NaN.
+func (c JSONCodec) Marshal(d Dec64) ([]byte, error) {
+	if c.NaN != "" && (d == NotAvailable || d.IsNaN()) {
+		return json.Marshal(c.NaN)
+	}
+	return d.MarshalJSON()
+}
+
+// Unmarshal data as Dec64.UnmarshalJSON does, the string c.NaN is NaN.
+func (c JSONCodec) Unmarshal(data []byte) (Dec64, error) {
+	var s string
+	if c.NaN != "" && json.Unmarshal(data, &s) == nil && s == c.NaN {
+		return NaN, nil
+	}
+	return unmarshalJSON(data, reflect.TypeOf(Dec64(0)))
+}
@@ -0,0 +1,185 @@
+package dec64
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	for _, s := range sVBench {
+		d, err := Parse(s)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		text, err := d.MarshalText()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var back Dec64
+		if err = back.UnmarshalText(text); err != nil {
+			t.Error(err)
+			return
+		}
+		if back != d {
+			t.Errorf("UnmarshalText(%s) is %s should be %s", text, back, d)
+		}
+	}
+	// map keys use text interfaces
+	m := map[Dec64]int{Dec64(125*256 + 254): 1, Dec64(3 * 256): 2}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != `{"1.25":1,"3":2}` {
+		t.Errorf("json.Marshal(map) is %s", data)
+	}
+	back := make(map[Dec64]int)
+	if err = json.Unmarshal(data, &back); err != nil {
+		t.Error(err)
+		return
+	}
+	for k, v := range m {
+		if back[k] != v {
+			t.Errorf("map[%s] is %d should be %d", k, back[k], v)
+		}
+	}
+	// special values
+	for _, d := range []Dec64{NaN, NotAvailable} {
+		text, err := d.MarshalText()
+		if err != nil || string(text) != "NaN" {
+			t.Errorf("MarshalText(%s) is %s, %v should be NaN", d, text, err)
+		}
+	}
+	for _, s := range []string{"NaN", "nan"} {
+		var d Dec64
+		if err = d.UnmarshalText([]byte(s)); err != nil || !d.IsNaN() {
+			t.Errorf("UnmarshalText(%s) is %s, %v should be NaN", s, d, err)
+		}
+	}
+	data, err = json.Marshal(map[Dec64]int{NaN: 1})
+	if err != nil || string(data) != `{"NaN":1}` {
+		t.Errorf("json.Marshal(NaN map) is %s, %v", data, err)
+	}
+	if err = json.Unmarshal(data, &back); err != nil || back[NaN] != 1 {
+		t.Errorf("json.Unmarshal(%s) is %v, %v", data, back, err)
+	}
+	// xml attributes
+	type quote struct {
+		Price Dec64 `xml:"price,attr"`
+	}
+	data, err = xml.Marshal(quote{Price: Dec64(125*256 + 254)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != `<quote price="1.25"></quote>` {
+		t.Errorf("xml.Marshal is %s", data)
+	}
+}
+
+func TestQuotedJSON(t *testing.T) {
+	type ticker struct {
+		Bid QuotedDec64 `json:"bid"`
+		Ask QuotedDec64 `json:"ask"`
+		Vol QuotedDec64 `json:"vol"`
+	}
+	var tick ticker
+	err := json.Unmarshal([]byte(`{"bid":"12.50","ask":12.6,"vol":null}`), &tick)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if tick.Bid.Dec64().String() != "12.5" || tick.Ask.Dec64().String() != "12.6" {
+		t.Errorf("bid %s ask %s should be 12.5 12.6", tick.Bid.Dec64(), tick.Ask.Dec64())
+	}
+	if tick.Vol.Dec64() != Empty {
+		t.Errorf("vol %s should be null", tick.Vol.Dec64())
+	}
+	data, err := json.Marshal(tick)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != `{"bid":"12.5","ask":"12.6","vol":null}` {
+		t.Errorf("json.Marshal is %s", data)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type quote struct {
+		Bid  Dec64   `json:"bid"`
+		Ask  Dec64   `json:"ask"`
+		Last Dec64   `json:"last"`
+		Hist []Dec64 `json:"hist"`
+	}
+	var q quote
+	err := json.Unmarshal([]byte(`{"bid":"1.5","ask":1.75,"last":null,"hist":[1,"2.5",null,"NaN"]}`), &q)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if q.Bid.String() != "1.5" || q.Ask.String() != "1.75" || q.Last != Empty {
+		t.Errorf("bid %s ask %s last %s should be 1.5 1.75 null", q.Bid, q.Ask, q.Last)
+	}
+	if len(q.Hist) != 4 || q.Hist[1].String() != "2.5" || q.Hist[2] != Empty || !q.Hist[3].IsNaN() {
+		t.Errorf("hist is %v", q.Hist)
+	}
+	data, err := json.Marshal(q)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != `{"bid":1.5,"ask":1.75,"last":null,"hist":[1,2.5,null,null]}` {
+		t.Errorf("json.Marshal is %s", data)
+	}
+	data, err = json.Marshal([]Dec64{NotAvailable, NaN, Dec64(7*256 + 1)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != `[null,null,70]` {
+		t.Errorf("json.Marshal is %s", data)
+	}
+	// spelled NaN
+	for _, c := range []struct {
+		codec JSONCodec
+		d     Dec64
+		ref   string
+		back  Dec64
+	}{
+		{JSONCodec{NaN: "NaN"}, NaN, `"NaN"`, NaN},
+		{JSONCodec{NaN: "NaN"}, NotAvailable, `"NaN"`, NaN},
+		{JSONCodec{NaN: "N/A"}, NaN, `"N/A"`, NaN},
+		{JSONCodec{NaN: "N/A"}, Empty, `null`, Empty},
+		{JSONCodec{NaN: "N/A"}, Dec64(7*256 + 1), `70`, Dec64(7*256 + 1)},
+		{JSONCodec{}, NaN, `null`, Empty},
+	} {
+		data, err = c.codec.Marshal(c.d)
+		if err != nil || string(data) != c.ref {
+			t.Errorf("Marshal(%#x) with %q is %s, %v should be %s", int64(c.d), c.codec.NaN, data, err, c.ref)
+			continue
+		}
+		back, err := c.codec.Unmarshal(data)
+		if err != nil || back != c.back {
+			t.Errorf("Unmarshal(%s) with %q is %#x, %v should be %#x", data, c.codec.NaN, int64(back), err, int64(c.back))
+		}
+	}
+	// blank values
+	var d Dec64
+	for _, s := range []string{`" "`, `"  "`} {
+		if err = json.Unmarshal([]byte(s), &d); err == nil {
+			t.Errorf("json.Unmarshal(%s) is %s should be in error", s, d)
+		}
+	}
+	// invalid tokens
+	for _, s := range []string{`{"bid":true}`, `{"bid":{}}`, `{"bid":[1]}`, `{"bid":"abc"}`} {
+		err = json.Unmarshal([]byte(s), &q)
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			t.Errorf("json.Unmarshal(%s) error is %v should be an UnmarshalTypeError", s, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dec64
+
+import "strconv"
+
+// SOH FIX field delimiter.
+const SOH = 0x01
+
+// FIXField returns value of first field tag in a FIX message
+// such as 35=D\x0144=101.25\x01, nil when missing.
+// Value is a slice of msg, nothing is allocated.
+func FIXField(msg []byte, tag int) []byte {
+	for i := 0; i < len(msg); {
+		// read tag number
+		t, j := 0, i
+		for ; j < len(msg) && msg[j] >= '0' && msg[j] <= '9'; j++ {
+			t = 10*t + int(msg[j]-'0')
+		}
+		// end of value
+		k := j
+		for k < len(msg) && msg[k] != SOH {
+			k++
+		}
+		if j > i && j < len(msg) && msg[j] == '=' && t == tag {
+			return msg[j+1 : k]
+		}
+		i = k + 1
+	}
+	return nil
+}
+
+// ParseFIX returns value of field tag in a FIX message as a dec64
+// without allocating, Empty when field is missing or empty.
+func ParseFIX(msg []byte, tag int) (Dec64, error) {
+	return ParseBytes(FIXField(msg, tag))
+}
+
+// AppendFIX appends d to b as a FIX field tag=value\x01 rounded to
+// maxDecimals digits after the dot (all when negative), trailing zeros
+// are not written. Nothing is appended for Empty, NotAvailable and NaN.
+func AppendFIX(b []byte, tag int, d Dec64, maxDecimals int) []byte {
+	if isSpecial(d) {
+		return b
+	}
+	decimals := 0
+	if exp := Normalize(d).Exponent(); exp < 0 {
+		decimals = -exp
+	}
+	if maxDecimals >= 0 && decimals > maxDecimals {
+		decimals = maxDecimals
+	}
+	b = strconv.AppendInt(b, int64(tag), 10)
+	b = append(b, '=')
+	b = appendFixed(b, d, decimals)
+	if decimals > 0 {
+		// rounding may give trailing zeros
+		for b[len(b)-1] == '0' {
+			b = b[:len(b)-1]
+		}
+		if b[len(b)-1] == '.' {
+			b = b[:len(b)-1]
+		}
+	}
+	return append(b, SOH)
+}
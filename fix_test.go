@@ -0,0 +1,84 @@
+package dec64
+
+import "testing"
+
+var fixMsg = []byte("8=FIX.4.4\x019=65\x0135=D\x01144=7\x0144=101.25\x0138=1e3\x0199=\x0110=123\x01")
+
+func testParseFIX(t *testing.T, tag int, ref string) {
+	d, err := ParseFIX(fixMsg, tag)
+	if err != nil || d.String() != ref {
+		t.Errorf("Tag %d gives %s (%v) should be %s", tag, d, err, ref)
+	}
+}
+
+func TestParseFIX(t *testing.T) {
+	testParseFIX(t, 44, "101.25")
+	testParseFIX(t, 144, "7")
+	testParseFIX(t, 38, "1000")
+	testParseFIX(t, 10, "123")
+	// empty and missing
+	testParseFIX(t, 99, "null")
+	testParseFIX(t, 4, "null")
+	if _, err := ParseFIX(fixMsg, 35); err == nil {
+		t.Errorf("35=D should be an error")
+	}
+	// blank values are errors, not crashes
+	for _, msg := range []string{"44= \x01", "35=D\x0144=   "} {
+		if d, err := ParseFIX([]byte(msg), 44); err == nil {
+			t.Errorf("%q gives %s without error", msg, d)
+		}
+	}
+	if v := FIXField(fixMsg, 8); string(v) != "FIX.4.4" {
+		t.Errorf("Tag 8 is %q", v)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		ParseFIX(fixMsg, 44)
+	})
+	if allocs != 0 {
+		t.Errorf("ParseFIX allocates %f times", allocs)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	b := []byte("-12.5e-3")
+	d, err := ParseBytes(b)
+	if err != nil || d.String() != "-0.0125" {
+		t.Errorf("ParseBytes gives %s (%v)", d, err)
+	}
+	for _, blank := range []string{" ", "   "} {
+		if _, err = ParseBytes([]byte(blank)); err == nil {
+			t.Errorf("%q should be an error", blank)
+		}
+		if _, err = Parse(blank); err == nil {
+			t.Errorf("%q should be an error", blank)
+		}
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		Parse("101.25")
+	})
+	if allocs != 0 {
+		t.Errorf("Parse allocates %f times", allocs)
+	}
+	_, err = ParseBytes([]byte("1x"))
+	if err == nil || err.Error() != "Unable to parse dec64 from 1x" {
+		t.Errorf("ParseBytes error is %v", err)
+	}
+}
+
+func testAppendFIX(t *testing.T, s string, maxDecimals int, ref string) {
+	d, _ := Parse(s)
+	b := AppendFIX([]byte("35=D\x01"), 44, d, maxDecimals)
+	if string(b) != "35=D\x01"+ref {
+		t.Errorf("%s with %d decimals gives %q should be %q", s, maxDecimals, b, "35=D\x01"+ref)
+	}
+}
+
+func TestAppendFIX(t *testing.T) {
+	testAppendFIX(t, "101.25", 4, "44=101.25\x01")
+	testAppendFIX(t, "101.256", 2, "44=101.26\x01")
+	testAppendFIX(t, "-0.004", 2, "44=0\x01")
+	testAppendFIX(t, "9.996", 2, "44=10\x01")
+	testAppendFIX(t, "1e3", 2, "44=1000\x01")
+	testAppendFIX(t, "0.000000123", -1, "44=0.000000123\x01")
+	testAppendFIX(t, "", 2, "")
+}
@@ -0,0 +1,161 @@
+package dec64
+
+import (
+	"strconv"
+	"strings"
+)
+
+// decompose returns digits of normalized coefficient and exponent of d.
+func decompose(d Dec64) (neg bool, digits []byte, exp int) {
+	d = Normalize(d)
+	coef := d.Coefficient()
+	if coef == 0 {
+		return false, []byte{'0'}, 0
+	}
+	exp = d.Exponent()
+	if coef < 0 {
+		neg = true
+		coef = -coef
+	}
+	digits = strconv.AppendInt(make([]byte, 0, 20), coef, 10)
+	return
+}
+
+// appendExp appends exponent as e+05.
+func appendExp(b []byte, x int) []byte {
+	b = append(b, 'e')
+	if x < 0 {
+		b = append(b, '-')
+		x = -x
+	} else {
+		b = append(b, '+')
+	}
+	if x < 10 {
+		b = append(b, '0')
+	}
+	return strconv.AppendInt(b, int64(x), 10)
+}
+
+// specialString returns Empty as String does,
+// NotAvailable and NaN as NaN like MarshalText.
+func specialString(d Dec64) string {
+	if d == Empty {
+		return d.String()
+	}
+	return "NaN"
+}
+
+// FormatSci returns d in scientific notation as 1.2345e+03.
+func (d Dec64) FormatSci() string {
+	if isSpecial(d) {
+		return specialString(d)
+	}
+	neg, digits, exp := decompose(d)
+	b := make([]byte, 0, len(digits)+8)
+	if neg {
+		b = append(b, '-')
+	}
+	b = append(b, digits[0])
+	if len(digits) > 1 {
+		b = append(b, '.')
+		b = append(b, digits[1:]...)
+	}
+	return string(appendExp(b, exp+len(digits)-1))
+}
+
+// FormatEng returns d in engineering notation, exponent is
+// a multiple of 3 as 12.345e+03.
+func (d Dec64) FormatEng() string {
+	if isSpecial(d) {
+		return specialString(d)
+	}
+	neg, digits, exp := decompose(d)
+	x := exp + len(digits) - 1
+	// floor to multiple of 3
+	ex := x - ((x%3)+3)%3
+	n := x - ex + 1
+	b := make([]byte, 0, len(digits)+10)
+	if neg {
+		b = append(b, '-')
+	}
+	if len(digits) <= n {
+		b = append(b, digits...)
+		for i := len(digits); i < n; i++ {
+			b = append(b, '0')
+		}
+	} else {
+		b = append(b, digits[:n]...)
+		b = append(b, '.')
+		b = append(b, digits[n:]...)
+	}
+	return string(appendExp(b, ex))
+}
+
+// FormatAuto returns d as String does, switching to scientific notation
+// when decimal exponent is below -threshold or at least threshold,
+// like %g does.
+func (d Dec64) FormatAuto(threshold int) string {
+	if isSpecial(d) {
+		return specialString(d)
+	}
+	_, digits, exp := decompose(d)
+	if x := exp + len(digits) - 1; x < -threshold || x >= threshold {
+		return d.FormatSci()
+	}
+	return d.String()
+}
+
+// FormatFixed returns d rounded to decimals digits after the dot,
+// right justified on width characters, NotAvailable and NaN are written as Empty.
+func FormatFixed(d Dec64, decimals, width int) string {
+	if isSpecial(d) {
+		return pad(Empty.String(), width)
+	}
+	return pad(string(appendFixed(make([]byte, 0, 32), d, decimals)), width)
+}
+
+// appendFixed appends d with exactly decimals digits after the dot.
+func appendFixed(b []byte, d Dec64, decimals int) []byte {
+	if decimals < 0 {
+		decimals = 0
+	}
+	neg, digits, exp := decompose(Round(d, int64(-decimals)))
+	if neg {
+		b = append(b, '-')
+	}
+	if exp >= 0 {
+		b = append(b, digits...)
+		for i := 0; i < exp; i++ {
+			b = append(b, '0')
+		}
+		if decimals > 0 {
+			b = append(b, '.')
+		}
+	} else {
+		m := -exp
+		if len(digits) > m {
+			b = append(b, digits[:len(digits)-m]...)
+			b = append(b, '.')
+			b = append(b, digits[len(digits)-m:]...)
+		} else {
+			b = append(b, '0', '.')
+			for i := len(digits); i < m; i++ {
+				b = append(b, '0')
+			}
+			b = append(b, digits...)
+		}
+		decimals -= m
+	}
+	for i := 0; i < decimals; i++ {
+		b = append(b, '0')
+	}
+	return b
+}
+
+// pad adds spaces in front of s up to width.
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
@@ -0,0 +1,78 @@
+package dec64
+
+import "testing"
+
+func testOneFormat(t *testing.T, s, sci, eng, auto string) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, ref := range []struct {
+		name, res, ref string
+	}{
+		{"FormatSci", d.FormatSci(), sci},
+		{"FormatEng", d.FormatEng(), eng},
+		{"FormatAuto", d.FormatAuto(21), auto},
+	} {
+		if ref.res != ref.ref {
+			t.Errorf("%s(%s) is %s should be %s", ref.name, s, ref.res, ref.ref)
+		}
+		back, err := Parse(ref.res)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if Normalize(back) != Normalize(d) {
+			t.Errorf("Parse(%s) is %s should be %s", ref.res, back, d)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testOneFormat(t, "0", "0e+00", "0e+00", "0")
+	testOneFormat(t, "1", "1e+00", "1e+00", "1")
+	testOneFormat(t, "1234.5", "1.2345e+03", "1.2345e+03", "1234.5")
+	testOneFormat(t, "12345", "1.2345e+04", "12.345e+03", "12345")
+	testOneFormat(t, "-0.00015", "-1.5e-04", "-150e-06", "-0.00015")
+	testOneFormat(t, "10000", "1e+04", "10e+03", "10000")
+	testOneFormat(t, "1e120", "1e+120", "1e+120", "1e+120")
+	testOneFormat(t, "-1e-100", "-1e-100", "-100e-102", "-1e-100")
+	testOneFormat(t, "3.1997721799999996e-40", "3.1997721799999996e-40",
+		"319.97721799999996e-42", "3.1997721799999996e-40")
+	// specials are never numbers
+	for d, ref := range map[Dec64]string{Empty: "null", NotAvailable: "NaN", NaN: "NaN"} {
+		if d.FormatSci() != ref || d.FormatEng() != ref || d.FormatAuto(6) != ref {
+			t.Errorf("%#x is written as %s %s %s should be %s",
+				int64(d), d.FormatSci(), d.FormatEng(), d.FormatAuto(6), ref)
+		}
+	}
+}
+
+func testOneFixed(t *testing.T, s string, decimals, width int, ref string) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if res := FormatFixed(d, decimals, width); res != ref {
+		t.Errorf("FormatFixed(%s, %d, %d) is '%s' should be '%s'", s, decimals, width, res, ref)
+	}
+}
+
+func TestFormatFixed(t *testing.T) {
+	testOneFixed(t, "1.5", 2, 0, "1.50")
+	testOneFixed(t, "1.5", 2, 8, "    1.50")
+	testOneFixed(t, "-1.456", 2, 8, "   -1.46")
+	testOneFixed(t, "0.004", 2, 0, "0.00")
+	testOneFixed(t, "0.005", 2, 0, "0.01")
+	testOneFixed(t, "0.00015", 5, 0, "0.00015")
+	testOneFixed(t, "1200", 1, 0, "1200.0")
+	testOneFixed(t, "1234.5", 0, 0, "1235")
+	testOneFixed(t, "", 2, 6, "  null")
+	for _, d := range []Dec64{NotAvailable, NaN} {
+		if res := FormatFixed(d, 2, 6); res != "  null" {
+			t.Errorf("FormatFixed(%#x, 2, 6) is '%s' should be '  null'", int64(d), res)
+		}
+	}
+}
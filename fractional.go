@@ -0,0 +1,180 @@
+package dec64
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrOffTick value is not a multiple of the smallest fractional tick.
+var ErrOffTick = errors.New("Value is not on a tick")
+
+// eighthDigits digit written for n eighths of a tick,
+// truncated decimal of n/8 as in 99-162 for 99 16.25/32.
+const eighthDigits = "01235678"
+
+// tickShift returns k such as 2^k is 8*denominator.
+func tickShift(denominator int) (k uint, err error) {
+	if denominator < 2 || denominator > 256 || denominator&(denominator-1) != 0 {
+		err = fmt.Errorf("Unsupported denominator %d", denominator)
+		return
+	}
+	for 1<<k != 8*denominator {
+		k++
+	}
+	return
+}
+
+// tickWidth returns number of digits used for ticks.
+func tickWidth(denominator int) int {
+	return len(strconv.Itoa(denominator - 1))
+}
+
+// ParseFractional returns a dec64 from a price written in
+// 1/denominator such as 99-16, 99-16+, 99-162 or 101'08.5 for 32nds.
+// Third digit (or +) is the number of eighths of a tick.
+func ParseFractional(s string, denominator int) (res Dec64, err error) {
+	res = Empty
+	k, err := tickShift(denominator)
+	if err != nil {
+		return
+	}
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	whole, ticks, sep := s, "", false
+	if i := strings.IndexAny(s, "-'"); i >= 0 {
+		whole, ticks, sep = s[:i], s[i+1:], true
+	}
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		err = ParseError(fmt.Errorf("Unable to parse fractional from %s", s))
+		return
+	}
+	// value in eighths of tick
+	var eighths uint64
+	// ticks are required after a separator
+	if sep {
+		width := tickWidth(denominator)
+		if len(ticks) < width {
+			err = ParseError(fmt.Errorf("Ticks %s should have %d digits", ticks, width))
+			return
+		}
+		var n uint64
+		n, err = strconv.ParseUint(ticks[:width], 10, 64)
+		if err != nil || n >= uint64(denominator) {
+			err = ParseError(fmt.Errorf("Unable to parse ticks from %s", ticks))
+			return
+		}
+		eighths = 8 * n
+		rest := ticks[width:]
+		switch {
+		case rest == "":
+		case rest == "+":
+			eighths += 4
+		case rest[0] == '.' && len(rest) > 1 && len(rest) < 19:
+			var f uint64
+			f, err = strconv.ParseUint(rest[1:], 10, 64)
+			if err != nil {
+				err = ParseError(fmt.Errorf("Unable to parse ticks from %s", ticks))
+				return
+			}
+			if (8*f)%uint64(Expi[len(rest)-1]) != 0 {
+				err = ErrOffTick
+				return
+			}
+			eighths += 8 * f / uint64(Expi[len(rest)-1])
+		case len(rest) == 1 && strings.IndexByte(eighthDigits, rest[0]) >= 0:
+			eighths += uint64(strings.IndexByte(eighthDigits, rest[0]))
+		default:
+			err = ParseError(fmt.Errorf("Unable to parse ticks from %s", ticks))
+			return
+		}
+	}
+	// eighths/2^k is exactly eighths*5^k/10^k
+	f := eighths
+	for i := uint(0); i < k; i++ {
+		f *= 5
+	}
+	e := int64(k)
+	for f != 0 && f%10 == 0 {
+		f /= 10
+		e--
+	}
+	if f == 0 {
+		e = 0
+	}
+	if w > (MaxCoefficient-f)/uint64(Expi[e]) {
+		err = ParseError(fmt.Errorf("%s is too big for dec64", s))
+		return
+	}
+	coef := int64(w)*Expi[e] + int64(f)
+	if neg {
+		coef = -coef
+	}
+	res = pack(coef, int(-e))
+	return
+}
+
+// FormatFractional returns d written in 1/denominator such as 99-16+
+// for 32nds, ErrOffTick is returned when d is not a multiple
+// of an eighth of tick.
+func FormatFractional(d Dec64, denominator int) (s string, err error) {
+	k, err := tickShift(denominator)
+	if err != nil {
+		return
+	}
+	if d == Empty {
+		return d.String(), nil
+	}
+	d = Normalize(d)
+	coef := d.Coefficient()
+	e := int64(d.Exponent())
+	neg := coef < 0
+	if neg {
+		coef = -coef
+	}
+	var whole, eighths uint64
+	switch {
+	case e >= 0:
+		if e > 18 || coef > math.MaxInt64/Expi[e] {
+			err = fmt.Errorf("%s is too big for fractional", d)
+			return
+		}
+		whole = uint64(coef * Expi[e])
+	case -e > int64(k):
+		err = ErrOffTick
+		return
+	default:
+		whole = uint64(coef / Expi[-e])
+		rem := uint64(coef%Expi[-e]) << k
+		if rem%uint64(Expi[-e]) != 0 {
+			err = ErrOffTick
+			return
+		}
+		eighths = rem / uint64(Expi[-e])
+	}
+	b := make([]byte, 0, 32)
+	if neg {
+		b = append(b, '-')
+	}
+	b = strconv.AppendUint(b, whole, 10)
+	b = append(b, '-')
+	ticks := strconv.FormatUint(eighths/8, 10)
+	for i := len(ticks); i < tickWidth(denominator); i++ {
+		b = append(b, '0')
+	}
+	b = append(b, ticks...)
+	switch sub := eighths % 8; sub {
+	case 0:
+	case 4:
+		b = append(b, '+')
+	default:
+		b = append(b, eighthDigits[sub])
+	}
+	return string(b), nil
+}
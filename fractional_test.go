@@ -0,0 +1,55 @@
+package dec64
+
+import "testing"
+
+func testOneFractional(t *testing.T, s string, denominator int, ref, refs string) {
+	d, err := ParseFractional(s, denominator)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if d.String() != ref {
+		t.Errorf("ParseFractional(%s, %d) is %s should be %s", s, denominator, d.String(), ref)
+	}
+	res, err := FormatFractional(d, denominator)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if res != refs {
+		t.Errorf("FormatFractional(%s, %d) is %s should be %s", ref, denominator, res, refs)
+	}
+}
+
+func TestFractional(t *testing.T) {
+	testOneFractional(t, "99-16", 32, "99.5", "99-16")
+	testOneFractional(t, "99-16+", 32, "99.515625", "99-16+")
+	testOneFractional(t, "99-165", 32, "99.515625", "99-16+")
+	testOneFractional(t, "99-162", 32, "99.5078125", "99-162")
+	testOneFractional(t, "99-167", 32, "99.5234375", "99-167")
+	testOneFractional(t, "101'08.5", 32, "101.265625", "101-08+")
+	testOneFractional(t, "101'08.25", 32, "101.2578125", "101-082")
+	testOneFractional(t, "100-01", 32, "100.03125", "100-01")
+	testOneFractional(t, "100", 32, "100", "100-00")
+	testOneFractional(t, "-0-31", 32, "-0.96875", "-0-31")
+	testOneFractional(t, "99-63", 64, "99.984375", "99-63")
+	testOneFractional(t, "99-63+", 64, "99.9921875", "99-63+")
+	testOneFractional(t, "12-3", 8, "12.375", "12-3")
+}
+
+func TestFractionalErrors(t *testing.T) {
+	for _, s := range []string{"99-32", "99-1", "99-164", "99-16.3", "x-16", "99-16++", "99-", "99'", "-99-"} {
+		if d, err := ParseFractional(s, 32); err == nil {
+			t.Errorf("ParseFractional(%s) is %s should be in error", s, d.String())
+		}
+	}
+	if _, err := ParseFractional("99-16", 30); err == nil {
+		t.Errorf("denominator 30 should be in error")
+	}
+	for _, s := range []string{"99.1", "99.001953125"} {
+		d, _ := Parse(s)
+		if res, err := FormatFractional(d, 32); err != ErrOffTick {
+			t.Errorf("FormatFractional(%s) is %s, %v should be %v", s, res, err, ErrOffTick)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package dec64
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Gorilla compression as described in "Gorilla: A Fast, Scalable,
+// In-Memory Time Series Database" (Facebook, 2015) applied to dec64
+// words. Each value is XORed with the previous one (0 for the first):
+//
+//	0                     same value
+//	10 bits               meaningful bits fit in previous window
+//	11 lead(5) len(6) bits new window, len 0 is 64
+//
+// Stream ends with control 11, lead 31 and len 0 which is no window.
+
+// gorillaNoWindow leading zeros before first window.
+const gorillaNoWindow = 0xff
+
+// errGorillaCorrupted stream is not a valid Gorilla stream.
+var errGorillaCorrupted = errors.New("Corrupted Gorilla stream")
+
+// bitWriter appends bits to a buffer, most significant first.
+type bitWriter struct {
+	b []byte
+	// free bits in last byte
+	free uint
+}
+
+// writeBits writes the n lower bits of v.
+func (w *bitWriter) writeBits(v uint64, n uint) {
+	for n > 0 {
+		if w.free == 0 {
+			w.b = append(w.b, 0)
+			w.free = 8
+		}
+		k := n
+		if k > w.free {
+			k = w.free
+		}
+		w.b[len(w.b)-1] |= byte((v>>(n-k))&(1<<k-1)) << (w.free - k)
+		w.free -= k
+		n -= k
+	}
+}
+
+// Compressor packs dec64 values one at a time.
+type Compressor struct {
+	w                 bitWriter
+	prev              uint64
+	leading, trailing uint
+	n                 int
+}
+
+// NewCompressor returns an empty compressor.
+func NewCompressor() *Compressor {
+	return &Compressor{leading: gorillaNoWindow}
+}
+
+// Append adds d to the stream.
+func (c *Compressor) Append(d Dec64) {
+	v := uint64(d)
+	xor := v ^ c.prev
+	c.prev = v
+	c.n++
+	if xor == 0 {
+		c.w.writeBits(0, 1)
+		return
+	}
+	leading := uint(bits.LeadingZeros64(xor))
+	trailing := uint(bits.TrailingZeros64(xor))
+	if leading > 31 {
+		// 5 bits
+		leading = 31
+	}
+	if c.leading != gorillaNoWindow && leading >= c.leading && trailing >= c.trailing {
+		c.w.writeBits(0x2, 2)
+		c.w.writeBits(xor>>c.trailing, 64-c.leading-c.trailing)
+		return
+	}
+	c.leading, c.trailing = leading, trailing
+	meaningful := 64 - leading - trailing
+	c.w.writeBits(0x3, 2)
+	c.w.writeBits(uint64(leading), 5)
+	// 64 is written as 0
+	c.w.writeBits(uint64(meaningful&63), 6)
+	c.w.writeBits(xor>>trailing, meaningful)
+}
+
+// Len returns number of appended values.
+func (c *Compressor) Len() int {
+	return c.n
+}
+
+// Bytes returns a terminated copy of the stream,
+// values may still be appended afterwards.
+func (c *Compressor) Bytes() []byte {
+	w := bitWriter{b: make([]byte, len(c.w.b), len(c.w.b)+2), free: c.w.free}
+	copy(w.b, c.w.b)
+	w.writeBits(0x3, 2)
+	w.writeBits(31, 5)
+	w.writeBits(0, 6)
+	return w.b
+}
+
+// Decompressor reads back values of a Gorilla stream.
+type Decompressor struct {
+	b []byte
+	// pos next bit to read
+	pos               uint
+	prev              uint64
+	leading, trailing uint
+	end               bool
+}
+
+// NewDecompressor returns a decompressor of stream b,
+// as returned by Compressor.Bytes.
+func NewDecompressor(b []byte) *Decompressor {
+	return &Decompressor{b: b, leading: gorillaNoWindow}
+}
+
+// readBits reads n bits, io.ErrUnexpectedEOF at end of buffer.
+func (d *Decompressor) readBits(n uint) (v uint64, err error) {
+	if d.pos+n > uint(len(d.b))*8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	for n > 0 {
+		left := 8 - d.pos%8
+		k := n
+		if k > left {
+			k = left
+		}
+		bits := uint64(d.b[d.pos/8]>>(left-k)) & (1<<k - 1)
+		v = v<<k | bits
+		d.pos += k
+		n -= k
+	}
+	return
+}
+
+// Next returns next value, io.EOF once end of stream is read
+// and io.ErrUnexpectedEOF if stream is not terminated.
+func (d *Decompressor) Next() (Dec64, error) {
+	if d.end {
+		return Empty, io.EOF
+	}
+	control, err := d.readBits(1)
+	if err != nil {
+		return Empty, err
+	}
+	if control == 0 {
+		return Dec64(d.prev), nil
+	}
+	if control, err = d.readBits(1); err != nil {
+		return Empty, err
+	}
+	if control == 1 {
+		var leading, meaningful uint64
+		if leading, err = d.readBits(5); err != nil {
+			return Empty, err
+		}
+		if meaningful, err = d.readBits(6); err != nil {
+			return Empty, err
+		}
+		if meaningful == 0 {
+			meaningful = 64
+		}
+		if leading+meaningful > 64 {
+			// end marker
+			d.end = true
+			return Empty, io.EOF
+		}
+		d.leading, d.trailing = uint(leading), uint(64-leading-meaningful)
+	} else if d.leading == gorillaNoWindow {
+		return Empty, errGorillaCorrupted
+	}
+	xor, err := d.readBits(64 - d.leading - d.trailing)
+	if err != nil {
+		return Empty, err
+	}
+	d.prev ^= xor << d.trailing
+	return Dec64(d.prev), nil
+}
@@ -0,0 +1,80 @@
+package dec64
+
+import (
+	"io"
+	"testing"
+)
+
+func testGorilla(t *testing.T, values []Dec64) []byte {
+	c := NewCompressor()
+	for _, d := range values {
+		c.Append(d)
+	}
+	if c.Len() != len(values) {
+		t.Errorf("Len is %d should be %d", c.Len(), len(values))
+	}
+	b := c.Bytes()
+	d := NewDecompressor(b)
+	for i, value := range values {
+		v, err := d.Next()
+		if err != nil || v != value {
+			t.Errorf("values[%d] is %s (%v) should be %s", i, v, err, value)
+			return b
+		}
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("End gives %v", err)
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("After end gives %v", err)
+	}
+	return b
+}
+
+func TestGorilla(t *testing.T) {
+	values := ticks(1000)
+	b := testGorilla(t, values)
+	if len(b) > 4*len(values) {
+		t.Errorf("%d values take %d bytes", len(values), len(b))
+	}
+	// end marker only
+	if b = testGorilla(t, nil); len(b) != 2 {
+		t.Errorf("Empty stream is %x", b)
+	}
+	testGorilla(t, []Dec64{0, 0, Empty, NaN, NotAvailable, -1, Dec64(MinCoefficient * 256), 1, 1})
+	// xor with 64 meaningful bits
+	testGorilla(t, []Dec64{Dec64(-1<<63 | 1), 0, Dec64(-1<<63 | 1)})
+	// same values are one bit each
+	if b = testGorilla(t, make([]Dec64, 80)); len(b) != 12 {
+		t.Errorf("80 zeros take %d bytes", len(b))
+	}
+}
+
+func TestGorillaAppend(t *testing.T) {
+	values := ticks(20)
+	c := NewCompressor()
+	for i, d := range values {
+		c.Append(d)
+		// Bytes does not stop the stream
+		dec := NewDecompressor(c.Bytes())
+		for j := 0; j <= i; j++ {
+			if v, err := dec.Next(); err != nil || v != values[j] {
+				t.Errorf("After %d values, values[%d] is %s (%v)", i+1, j, v, err)
+			}
+		}
+	}
+	b := c.Bytes()
+	// not terminated
+	d := NewDecompressor(b[:len(b)-3])
+	var err error
+	for err == nil {
+		_, err = d.Next()
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated stream gives %v", err)
+	}
+	// window reuse before any window
+	if _, err = NewDecompressor([]byte{0x80}).Next(); err == nil {
+		t.Errorf("Reused window should be an error")
+	}
+}
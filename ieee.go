@@ -0,0 +1,345 @@
+package dec64
+
+import "math/bits"
+
+// IEEE 754-2008 decimal formats parameters.
+const (
+	decimal64Bias    = 398
+	decimal64MaxCoef = 9999999999999999
+	decimal128Bias   = 6176
+	// quiet NaN, in high word for decimal128
+	decimalNaN = 0x7c00000000000000
+	// combination field values
+	decimalCombInf = 0x1e
+	decimalCombNaN = 0x1f
+)
+
+// 10^34 as decimal128 coefficient limit
+const (
+	pow34Hi = 0x0001ed09bead87c0
+	pow34Lo = 0x378d8e6400000000
+)
+
+var (
+	// binToDPD declet of 0..999
+	binToDPD [1000]uint16
+	// dpdToBin value of each declet
+	dpdToBin [1024]uint16
+)
+
+func init() {
+	for n := 0; n < 1000; n++ {
+		binToDPD[n] = encodeDeclet(uint16(n))
+	}
+	for v := 0; v < 1024; v++ {
+		dpdToBin[v] = decodeDeclet(uint16(v))
+	}
+}
+
+// encodeDeclet packs 3 digits in 10 bits densely packed decimal.
+func encodeDeclet(n uint16) uint16 {
+	d2, d1, d0 := n/100, n/10%10, n%10
+	b, c, d := d2>>2&1, d2>>1&1, d2&1
+	f, g, h := d1>>2&1, d1>>1&1, d1&1
+	j, k, m := d0>>2&1, d0>>1&1, d0&1
+	switch d2>>3<<2 | d1>>3<<1 | d0>>3 {
+	case 0:
+		return b<<9 | c<<8 | d<<7 | f<<6 | g<<5 | h<<4 | j<<2 | k<<1 | m
+	case 1:
+		return b<<9 | c<<8 | d<<7 | f<<6 | g<<5 | h<<4 | 0x8 | m
+	case 2:
+		return b<<9 | c<<8 | d<<7 | j<<6 | k<<5 | h<<4 | 0xa | m
+	case 3:
+		return b<<9 | c<<8 | d<<7 | 1<<6 | h<<4 | 0xe | m
+	case 4:
+		return j<<9 | k<<8 | d<<7 | f<<6 | g<<5 | h<<4 | 0xc | m
+	case 5:
+		return f<<9 | g<<8 | d<<7 | 1<<5 | h<<4 | 0xe | m
+	case 6:
+		return j<<9 | k<<8 | d<<7 | h<<4 | 0xe | m
+	}
+	return d<<7 | 3<<5 | h<<4 | 0xe | m
+}
+
+// decodeDeclet returns value of 10 bits densely packed decimal.
+func decodeDeclet(v uint16) uint16 {
+	b := func(i uint) uint16 { return v >> i & 1 }
+	var d2, d1, d0 uint16
+	switch {
+	case b(3) == 0:
+		d2, d1, d0 = v>>7&7, v>>4&7, v&7
+	case v>>1&3 == 0:
+		d2, d1, d0 = v>>7&7, v>>4&7, 8|b(0)
+	case v>>1&3 == 1:
+		d2, d1, d0 = v>>7&7, 8|b(4), v>>4&6|b(0)
+	case v>>1&3 == 2:
+		d2, d1, d0 = 8|b(7), v>>4&7, v>>7&6|b(0)
+	case v>>5&3 == 0:
+		d2, d1, d0 = 8|b(7), 8|b(4), v>>7&6|b(0)
+	case v>>5&3 == 1:
+		d2, d1, d0 = 8|b(7), v>>7&6|b(4), 8|b(0)
+	case v>>5&3 == 2:
+		d2, d1, d0 = v>>7&7, 8|b(4), 8|b(0)
+	default:
+		d2, d1, d0 = 8|b(7), 8|b(4), 8|b(0)
+	}
+	return 100*d2 + 10*d1 + d0
+}
+
+// isSpecial checks for values without decimal representation.
+func isSpecial(d Dec64) bool {
+	return d == Empty || d == NotAvailable || d.IsNaN()
+}
+
+// signCoef returns sign bit, absolute coefficient and exponent of d.
+func signCoef(d Dec64) (sign, coef uint64, exp int) {
+	c := d.Coefficient()
+	if c < 0 {
+		sign = 1
+		c = -c
+	}
+	return sign, uint64(c), d.Exponent()
+}
+
+// toDecimal64 returns coefficient and biased exponent of d
+// rounded to 16 digits.
+func toDecimal64(d Dec64) (sign, coef, exp uint64, err error) {
+	sign, coef, e := signCoef(d)
+	var rd uint64
+	sticky := false
+	for coef > decimal64MaxCoef {
+		sticky = sticky || rd != 0
+		rd = coef % 10
+		coef /= 10
+		e++
+	}
+	if RoundHalfEven.roundAway(sign == 1, coef&1 == 1, rd, sticky) {
+		coef++
+		if coef > decimal64MaxCoef {
+			coef /= 10
+			e++
+		}
+	}
+	if sticky || rd != 0 {
+		err = ErrInexact
+	}
+	return sign, coef, uint64(e + decimal64Bias), err
+}
+
+// ToDecimal64BID returns d as an IEEE 754 decimal64 with binary integer
+// coefficient (Intel), ErrInexact is returned along with the rounded
+// value when coefficient has more than 16 digits.
+// Empty, NotAvailable and NaN are NaN.
+func ToDecimal64BID(d Dec64) (uint64, error) {
+	if isSpecial(d) {
+		return decimalNaN, nil
+	}
+	sign, coef, exp, err := toDecimal64(d)
+	if coef < 1<<53 {
+		return sign<<63 | exp<<53 | coef, err
+	}
+	return sign<<63 | 3<<61 | exp<<51 | coef&(1<<51-1), err
+}
+
+// FromDecimal64BID returns a dec64 from an IEEE 754 decimal64 with binary
+// integer coefficient, ErrInexact is returned along with the rounded value
+// when exponent is too small, ErrRange for infinity or too big values.
+func FromDecimal64BID(v uint64) (Dec64, error) {
+	var (
+		coef uint64
+		exp  int
+	)
+	switch v >> 58 & 0x1f {
+	case decimalCombNaN:
+		return NaN, nil
+	case decimalCombInf:
+		return Empty, ErrRange
+	}
+	if v>>61&3 == 3 {
+		exp = int(v >> 51 & 0x3ff)
+		coef = 1<<53 | v&(1<<51-1)
+	} else {
+		exp = int(v >> 53 & 0x3ff)
+		coef = v & (1<<53 - 1)
+	}
+	if coef > decimal64MaxCoef {
+		// non canonical
+		coef = 0
+	}
+	return fit(v>>63 == 1, coef, exp-decimal64Bias, false, RoundHalfEven)
+}
+
+// ToDecimal64DPD returns d as an IEEE 754 decimal64 with densely packed
+// decimal coefficient (IBM), ErrInexact is returned along with the rounded
+// value when coefficient has more than 16 digits.
+// Empty, NotAvailable and NaN are NaN.
+func ToDecimal64DPD(d Dec64) (uint64, error) {
+	if isSpecial(d) {
+		return decimalNaN, nil
+	}
+	sign, coef, exp, err := toDecimal64(d)
+	lead := coef / 1e15
+	var trailing uint64
+	for i := uint(0); i < 5; i++ {
+		trailing |= uint64(binToDPD[coef%1000]) << (10 * i)
+		coef /= 1000
+	}
+	return sign<<63 | combination(lead, exp>>8)<<58 | (exp&0xff)<<50 | trailing, err
+}
+
+// combination returns 5 bits combination field from leading digit and
+// exponent 2 most significant bits.
+func combination(lead, exp uint64) uint64 {
+	if lead < 8 {
+		return exp<<3 | lead
+	}
+	return 3<<3 | exp<<1 | lead&1
+}
+
+// fromCombination returns leading digit and exponent 2 most significant
+// bits from 5 bits combination field.
+func fromCombination(g uint64) (lead, exp uint64) {
+	if g>>3 != 3 {
+		return g & 7, g >> 3
+	}
+	return 8 | g&1, g >> 1 & 3
+}
+
+// FromDecimal64DPD returns a dec64 from an IEEE 754 decimal64 with densely
+// packed decimal coefficient, ErrInexact is returned along with the rounded
+// value when exponent is too small, ErrRange for infinity or too big values.
+func FromDecimal64DPD(v uint64) (Dec64, error) {
+	g := v >> 58 & 0x1f
+	switch g {
+	case decimalCombNaN:
+		return NaN, nil
+	case decimalCombInf:
+		return Empty, ErrRange
+	}
+	coef, exp := fromCombination(g)
+	exp = exp<<8 | v>>50&0xff
+	for i := 4; i >= 0; i-- {
+		coef = 1000*coef + uint64(dpdToBin[v>>(10*uint(i))&0x3ff])
+	}
+	return fit(v>>63 == 1, coef, int(exp)-decimal64Bias, false, RoundHalfEven)
+}
+
+// ToDecimal128 returns d as an IEEE 754 decimal128 with binary integer
+// coefficient, as used by BSON, in high and low 64 bits words.
+// Conversion is always exact. Empty, NotAvailable and NaN are NaN.
+func ToDecimal128(d Dec64) (hi, lo uint64) {
+	if isSpecial(d) {
+		return decimalNaN, 0
+	}
+	sign, coef, exp := signCoef(d)
+	return sign<<63 | uint64(exp+decimal128Bias)<<49, coef
+}
+
+// FromDecimal128 returns a dec64 from an IEEE 754 decimal128 with binary
+// integer coefficient given as high and low 64 bits words.
+// ErrInexact is returned along with the rounded value when coefficient
+// has more than 17 digits, ErrRange for infinity or too big values.
+func FromDecimal128(hi, lo uint64) (Dec64, error) {
+	var exp int
+	switch hi >> 58 & 0x1f {
+	case decimalCombNaN:
+		return NaN, nil
+	case decimalCombInf:
+		return Empty, ErrRange
+	}
+	if hi>>61&3 == 3 {
+		// coefficient would be over 10^34: non canonical
+		hi, lo = 0, 0
+	} else {
+		exp = int(hi>>49&0x3fff) - decimal128Bias
+	}
+	sign := hi >> 63
+	hi &= 1<<49 - 1
+	if hi > pow34Hi || (hi == pow34Hi && lo >= pow34Lo) {
+		// non canonical
+		hi, lo = 0, 0
+	}
+	// digits dropped here are followed by more dropped in fit
+	sticky := false
+	for hi != 0 {
+		var r uint64
+		hi, r = hi/10, hi%10
+		lo, r = bits.Div64(r, lo, 10)
+		sticky = sticky || r != 0
+		exp++
+	}
+	return fit(sign == 1, lo, exp, sticky, RoundHalfEven)
+}
+
+// ToDecimal128DPD returns d as an IEEE 754 decimal128 with densely packed
+// decimal coefficient, in high and low 64 bits words.
+// Conversion is always exact. Empty, NotAvailable and NaN are NaN.
+func ToDecimal128DPD(d Dec64) (hi, lo uint64) {
+	if isSpecial(d) {
+		return decimalNaN, 0
+	}
+	sign, coef, e := signCoef(d)
+	exp := uint64(e + decimal128Bias)
+	// 17 digits fit in 6 declets of low word
+	for i := uint(0); i < 6; i++ {
+		lo |= uint64(binToDPD[coef%1000]) << (10 * i)
+		coef /= 1000
+	}
+	return sign<<63 | combination(0, exp>>12)<<58 | (exp&0xfff)<<46, lo
+}
+
+// FromDecimal128DPD returns a dec64 from an IEEE 754 decimal128 with densely
+// packed decimal coefficient given as high and low 64 bits words.
+// ErrInexact is returned along with the rounded value when coefficient
+// has more than 17 digits, ErrRange for infinity or too big values.
+func FromDecimal128DPD(hi, lo uint64) (Dec64, error) {
+	g := hi >> 58 & 0x1f
+	switch g {
+	case decimalCombNaN:
+		return NaN, nil
+	case decimalCombInf:
+		return Empty, ErrRange
+	}
+	lead, exp := fromCombination(g)
+	exp = exp<<12 | hi>>46&0xfff
+	// 34 digits
+	digits := make([]byte, 1, 34)
+	digits[0] = byte('0' + lead)
+	for i := 10; i >= 0; i-- {
+		shift := uint(10 * i)
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift > 54:
+			v = lo>>shift | hi<<(64-shift)
+		default:
+			v = lo >> shift
+		}
+		n := dpdToBin[v&0x3ff]
+		digits = append(digits, byte('0'+n/100), byte('0'+n/10%10), byte('0'+n%10))
+	}
+	return fitDigits(hi>>63 == 1, digits, int(exp)-decimal128Bias, RoundHalfEven)
+}
+
+// fitDigits returns a dec64 from decimal digits and an exponent,
+// rounding as fit does.
+func fitDigits(neg bool, digits []byte, exp int, mode RoundingMode) (Dec64, error) {
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	sticky := false
+	if len(digits) > 19 {
+		// keeping 19 digits ensures fit will drop more
+		for _, c := range digits[19:] {
+			sticky = sticky || c != '0'
+		}
+		exp += len(digits) - 19
+		digits = digits[:19]
+	}
+	var coef uint64
+	for _, c := range digits {
+		coef = 10*coef + uint64(c-'0')
+	}
+	return fit(neg, coef, exp, sticky, mode)
+}
@@ -0,0 +1,105 @@
+package dec64
+
+import "testing"
+
+func TestDeclets(t *testing.T) {
+	for n := uint16(0); n < 1000; n++ {
+		if v := dpdToBin[binToDPD[n]]; v != n {
+			t.Errorf("declet %d decoded as %d", n, v)
+		}
+	}
+	// some reference encodings
+	for n, ref := range map[uint16]uint16{0: 0, 9: 0x009, 10: 0x010, 99: 0x05f, 100: 0x080, 999: 0x0ff, 888: 0x06e} {
+		if binToDPD[n] != ref {
+			t.Errorf("declet of %d is %#x should be %#x", n, binToDPD[n], ref)
+		}
+	}
+}
+
+// testOneDecimal64 checks s encodings, rounded is the value read back
+// when s has more than 16 digits, empty if s is exact.
+func testOneDecimal64(t *testing.T, s string, bid, dpd uint64, rounded string) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ref, inexact := d, rounded != ""
+	if inexact {
+		if ref, err = Parse(rounded); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	var v uint64
+	for _, c := range []struct {
+		name string
+		to   func(Dec64) (uint64, error)
+		from func(uint64) (Dec64, error)
+		ref  uint64
+	}{
+		{"BID", ToDecimal64BID, FromDecimal64BID, bid},
+		{"DPD", ToDecimal64DPD, FromDecimal64DPD, dpd},
+	} {
+		v, err = c.to(d)
+		if (err == ErrInexact) != inexact || (err != nil && err != ErrInexact) {
+			t.Errorf("ToDecimal64%s(%s) error is %v", c.name, s, err)
+		}
+		if v != c.ref {
+			t.Errorf("ToDecimal64%s(%s) is %#x should be %#x", c.name, s, v, c.ref)
+		}
+		back, err := c.from(v)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if Normalize(back) != Normalize(ref) {
+			t.Errorf("FromDecimal64%s(%#x) is %s should be %s", c.name, v, back, ref)
+		}
+	}
+	hi, lo := ToDecimal128(d)
+	back, err := FromDecimal128(hi, lo)
+	if err != nil || back != d {
+		t.Errorf("FromDecimal128(ToDecimal128(%s)) is %s, %v", s, back, err)
+	}
+	hi, lo = ToDecimal128DPD(d)
+	back, err = FromDecimal128DPD(hi, lo)
+	if err != nil || back != d {
+		t.Errorf("FromDecimal128DPD(ToDecimal128DPD(%s)) is %s, %v", s, back, err)
+	}
+}
+
+func TestDecimal64(t *testing.T) {
+	testOneDecimal64(t, "1", 0x31c0000000000001, 0x2238000000000001, "")
+	testOneDecimal64(t, "-1.5", 0xb1a000000000000f, 0xa234000000000015, "")
+	testOneDecimal64(t, "0", 0x31c0000000000000, 0x2238000000000000, "")
+	testOneDecimal64(t, "9999999999999999", 0x6c7386f26fc0ffff, 0x6e38ff3fcff3fcff, "")
+	testOneDecimal64(t, "36028797018963967", 0x31eccccccccccccd, 0x2e3f02cff81c79fb, "3602879701896397e1")
+
+	hi, lo := ToDecimal128(Dec64(256))
+	if hi != 0x3040000000000000 || lo != 1 {
+		t.Errorf("ToDecimal128(1) is %#x %#x", hi, lo)
+	}
+	hi, lo = ToDecimal128DPD(Dec64(256))
+	if hi != 0x2208000000000000 || lo != 1 {
+		t.Errorf("ToDecimal128DPD(1) is %#x %#x", hi, lo)
+	}
+	hi, lo = ToDecimal128(NaN)
+	if d, err := FromDecimal128(hi, lo); err != nil || !d.IsNaN() {
+		t.Errorf("FromDecimal128(NaN) is %s, %v", d, err)
+	}
+	// infinity
+	if _, err := FromDecimal64BID(0x7800000000000000); err != ErrRange {
+		t.Errorf("FromDecimal64BID(Inf) error is %v should be %v", err, ErrRange)
+	}
+	// 10^33 with exponent -6176: far too small
+	if d, err := FromDecimal128(0x0000314dc6448d93, 0x38c15b0a00000000); err != ErrInexact || d != 0 {
+		t.Errorf("FromDecimal128(1e-6143) is %s, %v", d, err)
+	}
+	// 1234567890123456789012345678901234 E0 rounded to 17 digits
+	ref, _ := Parse("12345678901234568e17")
+	d, err := FromDecimal128(0x30403cde6fff9732, 0xde825cd07e96aff2)
+	if err != ErrInexact || d != ref {
+		t.Errorf("FromDecimal128 is %s, %v should be %s", d, err, ref)
+	}
+}
@@ -0,0 +1,316 @@
+package dec64
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// List file format, integers of header and footer are little endian:
+//
+//	magic    4 bytes  0x89 'D' '6' '4'
+//	version  1 byte
+//	flags    1 byte   listHasInfo, listBigEndian
+//	count    8 bytes  number of values
+//	info     if listHasInfo: name and unit (uvarint length and bytes),
+//	         scale (varint)
+//	values   count*8 bytes, big endian if listBigEndian
+//	crc      4 bytes  CRC32C (Castagnoli) of all previous bytes
+//
+// Files without magic are raw lists as written by ListToWriter.
+
+// ListMagic first bytes of a list file,
+// 0x89 is an unusual exponent so raw lists hardly start with it.
+const ListMagic = "\x89D64"
+
+// ListVersion version of list files written by WriteList.
+const ListVersion = 1
+
+// list file flags
+const (
+	listHasInfo = 1 << iota
+	listBigEndian
+	// listFlags known flags, others are rejected
+	listFlags = listHasInfo | listBigEndian
+)
+
+// List file errors.
+var (
+	ErrListChecksum = errors.New("List file checksum mismatch")
+	ErrListVersion  = errors.New("Unsupported list file version")
+)
+
+// listHeaderSize magic, version, flags and count.
+const listHeaderSize = len(ListMagic) + 2 + 8
+
+// castagnoli CRC32C table
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ListInfo optional metadata of a list file.
+type ListInfo struct {
+	// Name of the series.
+	Name string
+	// Unit of values such as EUR or bp.
+	Unit string
+	// Scale usual number of decimals of values.
+	Scale int
+	// Order of values, nil is little endian.
+	Order binary.ByteOrder
+}
+
+// hasMetadata tells if name, unit or scale are set.
+func (info *ListInfo) hasMetadata() bool {
+	return info != nil && (info.Name != "" || info.Unit != "" || info.Scale != 0)
+}
+
+// order returns byte order of values.
+func (info *ListInfo) order() binary.ByteOrder {
+	if info == nil {
+		return binary.LittleEndian
+	}
+	return byteOrder(info.Order)
+}
+
+// byteOrder returns order, little endian when nil.
+func byteOrder(order binary.ByteOrder) binary.ByteOrder {
+	if order == nil {
+		return binary.LittleEndian
+	}
+	return order
+}
+
+// WriteList writes values to w as a list file with optional info,
+// values are written in info order, binary.LittleEndian or binary.BigEndian.
+func WriteList(w io.Writer, values []Dec64, info *ListInfo) (err error) {
+	order := info.order()
+	if order != binary.LittleEndian && order != binary.BigEndian {
+		return fmt.Errorf("Unsupported list file byte order %v", order)
+	}
+	crc := crc32.New(castagnoli)
+	w = io.MultiWriter(w, crc)
+	header := make([]byte, listHeaderSize, 64)
+	copy(header, ListMagic)
+	header[4] = ListVersion
+	binary.LittleEndian.PutUint64(header[6:], uint64(len(values)))
+	if order == binary.BigEndian {
+		header[5] |= listBigEndian
+	}
+	if info.hasMetadata() {
+		header[5] |= listHasInfo
+		header = appendString(header, info.Name)
+		header = appendString(header, info.Unit)
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutVarint(buf[:], int64(info.Scale))
+		header = append(header, buf[:n]...)
+	}
+	if _, err = w.Write(header); err != nil {
+		return
+	}
+	e := NewEncoder(w)
+	e.Order = order
+	if err = e.Write(values...); err != nil {
+		return
+	}
+	if err = e.Flush(); err != nil {
+		return
+	}
+	footer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footer, crc.Sum32())
+	// crc of footer is never read
+	_, err = w.Write(footer)
+	return
+}
+
+// appendString appends uvarint length and bytes of s.
+func appendString(b []byte, s string) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(s)))
+	b = append(b, buf[:n]...)
+	return append(b, s...)
+}
+
+// ReadList reads a list file written by WriteList checking version,
+// count and checksum. Raw little endian lists written by ListToWriter
+// are read too. info is nil for them and for little endian files
+// without metadata, its Order is the one found in header.
+func ReadList(r io.Reader) (values []Dec64, info *ListInfo, err error) {
+	return ReadListOrder(r, binary.LittleEndian)
+}
+
+// ReadListOrder reads a list file as ReadList does,
+// raw lists without header are read in order.
+func ReadListOrder(r io.Reader, order binary.ByteOrder) (values []Dec64, info *ListInfo, err error) {
+	file, r, err := isListFile(r)
+	if err != nil {
+		return
+	}
+	if !file {
+		d := NewDecoder(r)
+		d.Order = byteOrder(order)
+		values, err = d.ReadAll()
+		return
+	}
+	return readListFile(r)
+}
+
+// isListFile tells if r starts with ListMagic,
+// returned reader still starts with first bytes of r.
+func isListFile(r io.Reader) (bool, io.Reader, error) {
+	magic := make([]byte, len(ListMagic))
+	n, err := io.ReadFull(r, magic)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, r, err
+	}
+	return string(magic[:n]) == ListMagic, io.MultiReader(bytes.NewReader(magic[:n]), r), nil
+}
+
+// readListFile reads a list file which magic was already checked.
+func readListFile(r io.Reader) (values []Dec64, info *ListInfo, err error) {
+	crc := crc32.New(castagnoli)
+	// no read ahead, footer is not part of crc
+	br := &crcReader{r: r, crc: crc}
+	magic := make([]byte, len(ListMagic))
+	if _, err = io.ReadFull(br, magic); err != nil {
+		return
+	}
+	header := make([]byte, listHeaderSize-len(ListMagic))
+	if err = readFull(br, header); err != nil {
+		return
+	}
+	if header[0] != ListVersion {
+		err = fmt.Errorf("%w %d", ErrListVersion, header[0])
+		return
+	}
+	flags := header[1]
+	if flags&^listFlags != 0 {
+		err = fmt.Errorf("Unknown list file flags 0x%02x", flags)
+		return
+	}
+	count := binary.LittleEndian.Uint64(header[2:])
+	order := binary.ByteOrder(binary.LittleEndian)
+	if flags&listBigEndian != 0 {
+		order = binary.BigEndian
+		info = &ListInfo{Order: order}
+	}
+	if flags&listHasInfo != 0 {
+		info = &ListInfo{Order: order}
+		if info.Name, err = readString(br); err != nil {
+			return
+		}
+		if info.Unit, err = readString(br); err != nil {
+			return
+		}
+		var scale int64
+		if scale, err = binary.ReadVarint(br); err != nil {
+			err = eofUnexpected(err)
+			return
+		}
+		info.Scale = int(scale)
+	}
+	// do not trust count for allocation
+	size := count
+	if size > 1<<16 {
+		size = 1 << 16
+	}
+	values = make([]Dec64, 0, size)
+	buf := make([]byte, 8*512)
+	for left := count; left > 0; {
+		n := uint64(len(buf) / 8)
+		if left < n {
+			n = left
+		}
+		if _, err = io.ReadFull(br, buf[:8*n]); err != nil {
+			err = eofUnexpected(err)
+			return
+		}
+		for i := uint64(0); i < n; i++ {
+			values = append(values, Dec64(order.Uint64(buf[8*i:])))
+		}
+		left -= n
+	}
+	sum := crc.Sum32()
+	footer := make([]byte, 4)
+	if _, err = io.ReadFull(r, footer); err != nil {
+		err = eofUnexpected(err)
+		return
+	}
+	if binary.LittleEndian.Uint32(footer) != sum {
+		err = ErrListChecksum
+	}
+	return
+}
+
+// readString reads uvarint length and bytes of a string.
+func readString(r io.ByteReader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", eofUnexpected(err)
+	}
+	if n > 1<<16 {
+		return "", fmt.Errorf("String of %d bytes is too long", n)
+	}
+	b := make([]byte, n)
+	err = readFull(r, b)
+	return string(b), err
+}
+
+// crcReader updates crc with bytes read.
+type crcReader struct {
+	r   io.Reader
+	crc hash.Hash32
+	one [1]byte
+}
+
+func (c *crcReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.crc.Write(p[:n])
+	return
+}
+
+// ReadByte reads a single byte, only used for header.
+func (c *crcReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(c, c.one[:])
+	return c.one[0], err
+}
+
+// ConvertList copies list from r to w with values in order to.
+// List files keep their metadata and are read in the order of their
+// header, raw lists are read in order from and streamed.
+func ConvertList(w io.Writer, r io.Reader, from, to binary.ByteOrder) (err error) {
+	file, r, err := isListFile(r)
+	if err != nil {
+		return
+	}
+	if file {
+		values, info, err := readListFile(r)
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			info = &ListInfo{}
+		}
+		info.Order = to
+		return WriteList(w, values, info)
+	}
+	d := NewDecoder(r)
+	d.Order = byteOrder(from)
+	e := NewEncoder(w)
+	e.Order = byteOrder(to)
+	buf := make([]Dec64, streamBufferSize/8)
+	for {
+		n, err := d.ReadBatch(buf)
+		if err == io.EOF {
+			return e.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		if err = e.Write(buf[:n]...); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package dec64
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func testReadList(t *testing.T, data []byte, values []Dec64, info *ListInfo) {
+	read, readInfo, err := ReadList(bytes.NewReader(data))
+	if err != nil {
+		t.Errorf("ReadList: %v", err)
+		return
+	}
+	if len(read) != len(values) {
+		t.Errorf("len is %d should be %d", len(read), len(values))
+		return
+	}
+	for i, value := range values {
+		if read[i] != value {
+			t.Errorf("read[%d] is %s should be %s", i, read[i], value)
+		}
+	}
+	if (info == nil) != (readInfo == nil) || (info != nil && *info != *readInfo) {
+		t.Errorf("Info is %+v should be %+v", readInfo, info)
+	}
+}
+
+func TestListFile(t *testing.T) {
+	values := make([]Dec64, 1000)
+	for i := range values {
+		values[i] = Dec64(int64(i*37-5000)<<8 | 0xfe)
+	}
+	info := &ListInfo{Name: "EURUSD bid", Unit: "USD", Scale: 5, Order: binary.LittleEndian}
+	var buf bytes.Buffer
+	if err := WriteList(&buf, values, info); err != nil {
+		t.Errorf("WriteList: %v", err)
+	}
+	data := append([]byte{}, buf.Bytes()...)
+	if string(data[:4]) != ListMagic || data[4] != ListVersion {
+		t.Errorf("Header is %x", data[:6])
+	}
+	testReadList(t, data, values, info)
+	// without info
+	buf.Reset()
+	WriteList(&buf, values[:3], nil)
+	if buf.Len() != listHeaderSize+3*8+4 {
+		t.Errorf("File size is %d", buf.Len())
+	}
+	testReadList(t, buf.Bytes(), values[:3], nil)
+	// raw lists are still read
+	buf.Reset()
+	ListToWriter(&buf, values[:10])
+	testReadList(t, buf.Bytes(), values[:10], nil)
+	testReadList(t, nil, nil, nil)
+
+	// corrupted value
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-10] ^= 1
+	if _, _, err := ReadList(bytes.NewReader(corrupted)); err != ErrListChecksum {
+		t.Errorf("Corrupted file gives %v", err)
+	}
+	// truncated
+	if _, _, err := ReadList(bytes.NewReader(data[:len(data)-6])); err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated file gives %v", err)
+	}
+	if _, _, err := ReadList(bytes.NewReader(data[:7])); err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated header gives %v", err)
+	}
+	// unknown version
+	corrupted = append([]byte{}, data...)
+	corrupted[4] = 9
+	if _, _, err := ReadList(bytes.NewReader(corrupted)); !errors.Is(err, ErrListVersion) {
+		t.Errorf("Version 9 gives %v", err)
+	}
+}
+
+func TestListOrder(t *testing.T) {
+	values := streamValues(700)
+	// big endian raw list
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	e.Order = binary.BigEndian
+	e.Write(values...)
+	e.Flush()
+	raw := append([]byte{}, buf.Bytes()...)
+	if binary.BigEndian.Uint64(raw) != uint64(values[0]) {
+		t.Errorf("First value is %x", raw[:8])
+	}
+	d := NewDecoder(bytes.NewReader(raw))
+	d.Order = binary.BigEndian
+	if read, err := d.ReadAll(); err != nil || read[699] != values[699] {
+		t.Errorf("Big endian ReadAll gives %v", err)
+	}
+	read, _, err := ReadListOrder(bytes.NewReader(raw), binary.BigEndian)
+	if err != nil || read[1] != values[1] {
+		t.Errorf("ReadListOrder gives %v", err)
+	}
+
+	// big endian list file, order detected from header
+	buf.Reset()
+	WriteList(&buf, values, &ListInfo{Order: binary.BigEndian})
+	data := append([]byte{}, buf.Bytes()...)
+	if data[5] != listBigEndian {
+		t.Errorf("Flags are 0x%02x", data[5])
+	}
+	testReadList(t, data, values, &ListInfo{Order: binary.BigEndian})
+	// unknown flags
+	corrupted := append([]byte{}, data...)
+	corrupted[5] |= 0x80
+	if _, _, err = ReadList(bytes.NewReader(corrupted)); err == nil {
+		t.Errorf("Unknown flags should be an error")
+	}
+
+	// convert raw big endian to little endian
+	buf.Reset()
+	if err = ConvertList(&buf, bytes.NewReader(raw), binary.BigEndian, binary.LittleEndian); err != nil {
+		t.Errorf("ConvertList: %v", err)
+	}
+	if read, err = ListFromReader(&buf); err != nil || len(read) != len(values) || read[5] != values[5] {
+		t.Errorf("Converted raw list gives %d values (%v)", len(read), err)
+	}
+	// convert list file keeping metadata
+	buf.Reset()
+	WriteList(&buf, values, &ListInfo{Name: "bid", Scale: 2})
+	var out bytes.Buffer
+	if err = ConvertList(&out, &buf, nil, binary.BigEndian); err != nil {
+		t.Errorf("ConvertList: %v", err)
+	}
+	testReadList(t, out.Bytes(), values, &ListInfo{Name: "bid", Scale: 2, Order: binary.BigEndian})
+	// nil order of raw list is little endian
+	buf.Reset()
+	ListToWriter(&buf, values)
+	out.Reset()
+	if err = ConvertList(&out, &buf, nil, binary.BigEndian); err != nil {
+		t.Errorf("ConvertList: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), raw) {
+		t.Errorf("Converted nil order raw list is not big endian")
+	}
+	// only orders known from header flags are written
+	type custom struct{ binary.ByteOrder }
+	if err = WriteList(&buf, values, &ListInfo{Order: custom{binary.BigEndian}}); err == nil {
+		t.Errorf("Custom byte order should be an error")
+	}
+}
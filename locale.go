@@ -0,0 +1,206 @@
+package dec64
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale describes how numbers are written in a given country.
+type Locale struct {
+	// Decimal separator, "." when empty.
+	Decimal string
+	// Group separator, no grouping when empty.
+	Group string
+	// Grouping sizes starting from the decimal separator,
+	// last one is repeated.
+	Grouping []int
+	// Minus sign, "-" when empty.
+	Minus string
+	// MinusAfter writes minus sign after the number.
+	MinusAfter bool
+}
+
+// Built-in locales.
+var (
+	// LocaleEN 1,234,567.89
+	LocaleEN = Locale{Decimal: ".", Group: ",", Grouping: []int{3}, Minus: "-"}
+	// LocaleFR 1 234 567,89 with narrow no-break space
+	LocaleFR = Locale{Decimal: ",", Group: "\u202f", Grouping: []int{3}, Minus: "-"}
+	// LocaleDE 1.234.567,89
+	LocaleDE = Locale{Decimal: ",", Group: ".", Grouping: []int{3}, Minus: "-"}
+	// LocaleCH 1'234'567.89
+	LocaleCH = Locale{Decimal: ".", Group: "'", Grouping: []int{3}, Minus: "-"}
+	// LocaleIN 12,34,567.89 (lakh grouping)
+	LocaleIN = Locale{Decimal: ".", Group: ",", Grouping: []int{3, 2}, Minus: "-"}
+)
+
+// Separators considered as the same when parsing.
+var (
+	spaceGroups      = []string{" ", "\u00a0", "\u202f"}
+	apostropheGroups = []string{"'", "\u2019"}
+)
+
+func (l *Locale) decimal() string {
+	if l.Decimal == "" {
+		return "."
+	}
+	return l.Decimal
+}
+
+func (l *Locale) minus() string {
+	if l.Minus == "" {
+		return "-"
+	}
+	return l.Minus
+}
+
+// groupSize returns size of i-th group starting from the decimal separator,
+// non positive sizes are ignored in favor of the default 3.
+func (l *Locale) groupSize(i int) int {
+	if len(l.Grouping) == 0 {
+		return 3
+	}
+	if i >= len(l.Grouping) {
+		i = len(l.Grouping) - 1
+	}
+	if l.Grouping[i] <= 0 {
+		return 3
+	}
+	return l.Grouping[i]
+}
+
+// groupAt returns length of group separator at the beginning of s, or 0.
+func (l *Locale) groupAt(s string) int {
+	if l.Group == "" {
+		return 0
+	}
+	if strings.HasPrefix(s, l.Group) {
+		return len(l.Group)
+	}
+	for _, alts := range [][]string{spaceGroups, apostropheGroups} {
+		if !contains(alts, l.Group) {
+			continue
+		}
+		for _, alt := range alts {
+			if strings.HasPrefix(s, alt) {
+				return len(alt)
+			}
+		}
+	}
+	return 0
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLocale returns a dec64 from a string written with locale l.
+func ParseLocale(s string, l Locale) (res Dec64, err error) {
+	res = Empty
+	s = strings.TrimSpace(s)
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, l.minus()):
+		neg = true
+		s = s[len(l.minus()):]
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case l.MinusAfter && strings.HasSuffix(s, l.minus()):
+		neg = true
+		s = s[:len(s)-len(l.minus())]
+	}
+	b := make([]byte, 0, len(s)+1)
+	if neg {
+		b = append(b, '-')
+	}
+	// digits count of each group in integer part
+	groups := make([]int, 1, 4)
+	dot := false
+	for i := 0; i < len(s); {
+		if !dot && strings.HasPrefix(s[i:], l.decimal()) {
+			dot = true
+			b = append(b, '.')
+			i += len(l.decimal())
+			continue
+		}
+		if n := l.groupAt(s[i:]); n > 0 {
+			if dot {
+				err = ParseError(fmt.Errorf("Unexpected group separator in %s", s))
+				return
+			}
+			groups = append(groups, 0)
+			i += n
+			continue
+		}
+		if !dot && s[i] >= '0' && s[i] <= '9' {
+			groups[len(groups)-1]++
+		}
+		b = append(b, s[i])
+		i++
+	}
+	if len(groups) > 1 {
+		for i := range groups {
+			size := l.groupSize(len(groups) - 1 - i)
+			if groups[i] == 0 || groups[i] > size || (i > 0 && groups[i] != size) {
+				err = ParseError(fmt.Errorf("Wrong grouping in %s", s))
+				return
+			}
+		}
+	}
+	return Parse(string(b))
+}
+
+// FormatLocale returns d as a string written with locale l.
+func (d Dec64) FormatLocale(l Locale) string {
+	s := d.String()
+	if d == Empty {
+		return s
+	}
+	neg := s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	var sb strings.Builder
+	sb.Grow(2 * len(s))
+	if neg && !l.MinusAfter {
+		sb.WriteString(l.minus())
+	}
+	if l.Group == "" {
+		sb.WriteString(integer)
+	} else {
+		// split integer part starting from the end
+		bounds := make([]int, 0, len(integer))
+		for i, end := 0, len(integer); end > 0; i++ {
+			bounds = append(bounds, end)
+			end -= l.groupSize(i)
+		}
+		start := 0
+		for i := len(bounds) - 1; i >= 0; i-- {
+			if start > 0 {
+				sb.WriteString(l.Group)
+			}
+			sb.WriteString(integer[start:bounds[i]])
+			start = bounds[i]
+		}
+	}
+	if fraction != "" {
+		sb.WriteString(l.decimal())
+		sb.WriteString(fraction)
+	}
+	if neg && l.MinusAfter {
+		sb.WriteString(l.minus())
+	}
+	return sb.String()
+}
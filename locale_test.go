@@ -0,0 +1,47 @@
+package dec64
+
+import "testing"
+
+func testOneLocale(t *testing.T, l Locale, s, refs, ref string) {
+	d, err := ParseLocale(s, l)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if d.String() != ref {
+		t.Errorf("ParseLocale(%s) is %s should be %s", s, d.String(), ref)
+	}
+	if d.FormatLocale(l) != refs {
+		t.Errorf("FormatLocale(%s) is %s should be %s", ref, d.FormatLocale(l), refs)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	testOneLocale(t, LocaleEN, "1,234,567.89", "1,234,567.89", "1234567.89")
+	testOneLocale(t, LocaleEN, "-1234.5", "-1,234.5", "-1234.5")
+	testOneLocale(t, LocaleEN, "999", "999", "999")
+	testOneLocale(t, LocaleEN, "0.001", "0.001", "0.001")
+	testOneLocale(t, LocaleDE, "1.234.567,89", "1.234.567,89", "1234567.89")
+	testOneLocale(t, LocaleDE, "-0,5", "-0,5", "-0.5")
+	testOneLocale(t, LocaleFR, "1 234,5", "1\u202f234,5", "1234.5")
+	testOneLocale(t, LocaleFR, "1\u00a0234\u00a0567", "1\u202f234\u202f567", "1234567")
+	testOneLocale(t, LocaleCH, "1'234'567.89", "1'234'567.89", "1234567.89")
+	testOneLocale(t, LocaleCH, "1\u2019234.5", "1'234.5", "1234.5")
+	testOneLocale(t, LocaleIN, "12,34,567.89", "12,34,567.89", "1234567.89")
+	testOneLocale(t, LocaleIN, "1,00,00,000", "1,00,00,000", "10000000")
+	trailing := Locale{Decimal: ",", Minus: "-", MinusAfter: true}
+	testOneLocale(t, trailing, "12,5-", "12,5-", "-12.5")
+	zero := Locale{Decimal: ".", Minus: "-", Group: ",", Grouping: []int{0}}
+	testOneLocale(t, zero, "1,234,567", "1,234,567", "1234567")
+}
+
+func TestLocaleErrors(t *testing.T) {
+	for _, s := range []string{"1,23,456", "12,3456", "1,234.5,6", ",123"} {
+		if d, err := ParseLocale(s, LocaleEN); err == nil || d != Empty {
+			t.Errorf("ParseLocale(%s) is %s (%v) should be in error", s, d.String(), err)
+		}
+	}
+	if d, err := ParseLocale("1.234,5", LocaleEN); err == nil {
+		t.Errorf("ParseLocale(1.234,5) is %s should be in error", d.String())
+	}
+}
@@ -0,0 +1,102 @@
+//go:build linux
+// +build linux
+
+package dec64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"syscall"
+	"unsafe"
+)
+
+// MappedList is a read-only memory-mapped list file
+// as written by ListToWriter.
+type MappedList struct {
+	data   []byte
+	values []Dec64
+}
+
+// littleEndianHost tells if dec64 words of a list can be used in place.
+func littleEndianHost() bool {
+	one := uint16(1)
+	return *(*byte)(unsafe.Pointer(&one)) == 1
+}
+
+// OpenMappedList maps file at path, its size must be a multiple of 8.
+// List files with a header as written by WriteList are refused,
+// host must be little endian for values to be used in place.
+func OpenMappedList(path string) (m *MappedList, err error) {
+	if !littleEndianHost() {
+		return nil, errors.New("Mapped lists need a little endian host")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return
+	}
+	size := fi.Size()
+	if size%8 != 0 {
+		return nil, fmt.Errorf("%s: size %d is not a multiple of 8: %w", path, size, ErrPartialRecord)
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("%s: size %d is too big to be mapped", path, size)
+	}
+	m = &MappedList{}
+	if size == 0 {
+		// empty mapping is not allowed
+		return
+	}
+	m.data, err = syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if string(m.data[:4]) == ListMagic {
+		m.Close()
+		return nil, fmt.Errorf("%s is a list file with header, use ReadList", path)
+	}
+	if uintptr(unsafe.Pointer(&m.data[0]))%8 != 0 {
+		m.Close()
+		return nil, fmt.Errorf("%s: mapping is not aligned on 8 bytes", path)
+	}
+	// overlay values on mapped memory
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&m.values))
+	h.Data = uintptr(unsafe.Pointer(&m.data[0]))
+	h.Len = len(m.data) / 8
+	h.Cap = h.Len
+	return
+}
+
+// Len returns number of values.
+func (m *MappedList) Len() int {
+	return len(m.data) / 8
+}
+
+// At returns value i, it panics if i is out of range.
+func (m *MappedList) At(i int) Dec64 {
+	return Dec64(binary.LittleEndian.Uint64(m.data[8*i : 8*i+8]))
+}
+
+// Values returns values without copy, slice is read-only
+// and must not be used after Close.
+func (m *MappedList) Values() []Dec64 {
+	return m.values
+}
+
+// Close unmaps file, it may be called several times.
+func (m *MappedList) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	m.values = nil
+	data := m.data
+	m.data = nil
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,78 @@
+//go:build linux
+// +build linux
+
+package dec64
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemp(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappedList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dec64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	values := streamValues(5000)
+	f, err := os.Create(filepath.Join(dir, "list"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ListToWriter(f, values)
+	f.Close()
+	m, err := OpenMappedList(f.Name())
+	if err != nil {
+		t.Errorf("OpenMappedList: %v", err)
+		return
+	}
+	if m.Len() != len(values) {
+		t.Errorf("Len is %d should be %d", m.Len(), len(values))
+	}
+	view := m.Values()
+	for i, value := range values {
+		if m.At(i) != value || view[i] != value {
+			t.Errorf("values[%d] is %s and %s should be %s", i, m.At(i), view[i], value)
+			break
+		}
+	}
+	if err = m.Close(); err != nil || m.Values() != nil || m.Len() != 0 {
+		t.Errorf("Close gives %v", err)
+	}
+	if err = m.Close(); err != nil {
+		t.Errorf("Second Close gives %v", err)
+	}
+
+	// empty file
+	m, err = OpenMappedList(writeTemp(t, dir, "empty", nil))
+	if err != nil || m.Len() != 0 || len(m.Values()) != 0 {
+		t.Errorf("Empty file gives %v", err)
+	} else {
+		m.Close()
+	}
+	// partial record
+	_, err = OpenMappedList(writeTemp(t, dir, "partial", make([]byte, 12)))
+	if !errors.Is(err, ErrPartialRecord) {
+		t.Errorf("12 bytes file gives %v", err)
+	}
+	// list file with header
+	data := append([]byte(ListMagic), make([]byte, 20)...)
+	if _, err = OpenMappedList(writeTemp(t, dir, "header", data)); err == nil || errors.Is(err, ErrPartialRecord) {
+		t.Errorf("List file with header should be an error")
+	}
+	if _, err = OpenMappedList(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Missing file should be an error")
+	}
+}
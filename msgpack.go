@@ -0,0 +1,175 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// MsgpackExtType default MessagePack extension type of dec64 values.
+const MsgpackExtType int8 = 1
+
+// MsgpackCodec reads and writes dec64 as MessagePack extensions of ExtType,
+// application specific type chosen by the users of the data.
+type MsgpackCodec struct {
+	ExtType int8
+}
+
+// msgpackDefault codec used by package functions.
+var msgpackDefault = MsgpackCodec{ExtType: MsgpackExtType}
+
+// MessagePack formats used for dec64.
+const (
+	msgpackNil     = 0xc0
+	msgpackFixExt8 = 0xd7
+	msgpackArray16 = 0xdc
+	msgpackArray32 = 0xdd
+)
+
+// AppendMsgpack appends d to b as a MessagePack fixext 8 of type
+// MsgpackExtType: exponent byte followed by the 56 bits big endian
+// coefficient. Empty is nil.
+func AppendMsgpack(b []byte, d Dec64) []byte {
+	return msgpackDefault.Append(b, d)
+}
+
+// Append appends d to b as AppendMsgpack does with c.ExtType.
+func (c MsgpackCodec) Append(b []byte, d Dec64) []byte {
+	if d == Empty {
+		return append(b, msgpackNil)
+	}
+	b = append(b, msgpackFixExt8, byte(c.ExtType), byte(d), 0, 0, 0, 0, 0, 0, 0)
+	coef := uint64(d.Coefficient())
+	for i := 1; i <= 7; i++ {
+		b[len(b)-i] = byte(coef)
+		coef >>= 8
+	}
+	return b
+}
+
+// ReadMsgpack reads a dec64 written by AppendMsgpack,
+// integers are accepted too. ErrInexact is returned along with
+// the rounded value when an integer has too many digits.
+// io.EOF is returned only if no byte was read.
+func ReadMsgpack(r io.ByteReader) (Dec64, error) {
+	return msgpackDefault.Read(r)
+}
+
+// Read reads a dec64 as ReadMsgpack does with c.ExtType.
+func (c MsgpackCodec) Read(r io.ByteReader) (d Dec64, err error) {
+	ib, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	var buf [9]byte
+	switch {
+	case ib == msgpackNil:
+		return Empty, nil
+	case ib <= 0x7f:
+		// positive fixint
+		return pack(int64(ib), 0), nil
+	case ib >= 0xe0:
+		// negative fixint
+		return pack(int64(int8(ib)), 0), nil
+	case ib >= 0xcc && ib <= 0xd3:
+		// uint8 to uint64 then int8 to int64
+		n := 1 << ((ib - 0xcc) & 3)
+		if err = readFull(r, buf[8-n:8]); err != nil {
+			return
+		}
+		v := binary.BigEndian.Uint64(buf[:8])
+		if ib >= 0xd0 {
+			// sign extension
+			shift := uint(64 - 8*n)
+			return fromBigExact(big.NewInt(int64(v<<shift)>>shift), 0)
+		}
+		return fromBigExact(new(big.Int).SetUint64(v), 0)
+	case ib == msgpackFixExt8:
+		if err = readFull(r, buf[:]); err != nil {
+			return
+		}
+		if int8(buf[0]) != c.ExtType {
+			err = fmt.Errorf("Unexpected MessagePack extension type %d", int8(buf[0]))
+			return
+		}
+		// exponent then coefficient is coefficient then exponent rotated
+		v := binary.BigEndian.Uint64(buf[1:])
+		return Dec64(v<<8 | v>>56), nil
+	}
+	err = fmt.Errorf("Unexpected MessagePack format 0x%02x for a dec64", ib)
+	return
+}
+
+// ListToMsgpack sends list of dec64 to writer as a MessagePack array.
+func ListToMsgpack(w io.Writer, values []Dec64) error {
+	return msgpackDefault.ListTo(w, values)
+}
+
+// ListTo sends list of dec64 to writer as ListToMsgpack does with c.ExtType.
+func (c MsgpackCodec) ListTo(w io.Writer, values []Dec64) (err error) {
+	buff := make([]byte, 0, 256)
+	n := len(values)
+	switch {
+	case n < 16:
+		buff = append(buff, 0x90|byte(n))
+	case n <= 0xffff:
+		buff = append(buff, msgpackArray16, byte(n>>8), byte(n))
+	default:
+		buff = append(buff, msgpackArray32, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	for _, v := range values {
+		buff = c.Append(buff, v)
+		if len(buff) >= 240 {
+			if _, err = w.Write(buff); err != nil {
+				return
+			}
+			buff = buff[:0]
+		}
+	}
+	_, err = w.Write(buff)
+	return
+}
+
+// ListFromMsgpack returns list of dec64 from a MessagePack array.
+// Reading stops on first error but ErrInexact which is reported
+// once all values are read. Errors are wrapped with the index
+// of the value, use errors.Is.
+func ListFromMsgpack(r io.Reader) ([]Dec64, error) {
+	return msgpackDefault.ListFrom(r)
+}
+
+// ListFrom returns list of dec64 as ListFromMsgpack does with c.ExtType.
+func (c MsgpackCodec) ListFrom(r io.Reader) (values []Dec64, err error) {
+	br := byteReader(r)
+	ib, err := br.ReadByte()
+	if err != nil {
+		return
+	}
+	var n int
+	switch {
+	case ib&0xf0 == 0x90:
+		n = int(ib & 0x0f)
+	case ib == msgpackArray16 || ib == msgpackArray32:
+		buf := make([]byte, 2<<(ib-msgpackArray16))
+		if err = readFull(br, buf); err != nil {
+			return
+		}
+		for _, b := range buf {
+			n = n<<8 | int(b)
+		}
+	default:
+		err = fmt.Errorf("Unexpected MessagePack format 0x%02x for a list", ib)
+		return
+	}
+	values = make([]Dec64, 0, 16)
+	_, err = convertAll(n, func(i int) error {
+		d, err := c.Read(br)
+		if err != nil && err != ErrInexact {
+			return eofUnexpected(err)
+		}
+		values = append(values, d)
+		return err
+	})
+	return
+}
@@ -0,0 +1,81 @@
+package dec64
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func testMsgpack(t *testing.T, d Dec64, ref []byte) {
+	b := AppendMsgpack(nil, d)
+	if !bytes.Equal(b, ref) {
+		t.Errorf("%s encoded as %x should be %x", d, b, ref)
+	}
+	res, err := ReadMsgpack(bytes.NewReader(b))
+	if err != nil || res != d {
+		t.Errorf("%x decoded as %s (%v) should be %s", b, res, err, d)
+	}
+}
+
+func testMsgpackRead(t *testing.T, b []byte, ref string, referr error) {
+	res, err := ReadMsgpack(bytes.NewReader(b))
+	if err != referr || (err == nil && res.String() != ref) {
+		t.Errorf("%x decoded as %s (%v) should be %s (%v)", b, res, err, ref, referr)
+	}
+}
+
+func TestMsgpack(t *testing.T) {
+	testMsgpack(t, Dec64(27315*256+254), []byte{0xd7, 0x01, 0xfe, 0, 0, 0, 0, 0, 0x6a, 0xb3})
+	testMsgpack(t, Dec64(-1*256+3), []byte{0xd7, 0x01, 0x03, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	testMsgpack(t, Empty, []byte{0xc0})
+	testMsgpack(t, NaN, []byte{0xd7, 0x01, 0x80, 0, 0, 0, 0, 0, 0, 0})
+	testMsgpack(t, NotAvailable, []byte{0xd7, 0x01, 0xff, 0, 0, 0, 0, 0, 0, 0})
+	// integers
+	testMsgpackRead(t, []byte{0x2a}, "42", nil)
+	testMsgpackRead(t, []byte{0xff}, "-1", nil)
+	testMsgpackRead(t, []byte{0xcd, 0x01, 0x00}, "256", nil)
+	testMsgpackRead(t, []byte{0xd1, 0xff, 0x00}, "-256", nil)
+	testMsgpackRead(t, []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "18446744073709551620", ErrInexact)
+	// wrong extension type
+	if _, err := ReadMsgpack(bytes.NewReader([]byte{0xd7, 0x02, 0, 0, 0, 0, 0, 0, 0, 0})); err == nil {
+		t.Errorf("Extension type 2 should be an error")
+	}
+	if _, err := ReadMsgpack(bytes.NewReader([]byte{0xd7, 0x01, 0})); err != io.ErrUnexpectedEOF {
+		t.Errorf("Truncated gives %v", err)
+	}
+	// application extension type
+	c := MsgpackCodec{ExtType: 2}
+	b := c.Append(nil, Dec64(-1*256+3))
+	if !bytes.Equal(b, []byte{0xd7, 0x02, 0x03, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("-1e3 encoded as %x with extension type 2", b)
+	}
+	if d, err := c.Read(bytes.NewReader(b)); err != nil || d != Dec64(-1*256+3) {
+		t.Errorf("%x decoded as %s (%v) with extension type 2", b, d, err)
+	}
+	if _, err := ReadMsgpack(bytes.NewReader(b)); err == nil {
+		t.Errorf("Extension type 2 should be an error by default")
+	}
+}
+
+func TestMsgpackList(t *testing.T) {
+	for _, n := range []int{0, 5, 300} {
+		values := make([]Dec64, n)
+		for i := range values {
+			values[i] = Dec64(int64(i*7-100)<<8 | 0xfd)
+		}
+		var buf bytes.Buffer
+		if err := ListToMsgpack(&buf, values); err != nil {
+			t.Errorf("ListToMsgpack: %v", err)
+		}
+		res, err := ListFromMsgpack(&buf)
+		if err != nil || len(res) != n {
+			t.Errorf("ListFromMsgpack gives %d values (%v) should be %d", len(res), err, n)
+			continue
+		}
+		for i := range values {
+			if res[i] != values[i] {
+				t.Errorf("values[%d] is %s should be %s", i, res[i], values[i])
+			}
+		}
+	}
+}
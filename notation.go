@@ -0,0 +1,188 @@
+package dec64
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Notation flags for decorations understood around a number.
+type Notation uint8
+
+const (
+	// Accounting negative numbers written in parentheses: (1,250.00)
+	Accounting Notation = 1 << iota
+	// Percent trailing %, 12.5% is 0.125
+	Percent
+	// BasisPoints trailing bp or bps, 35bp is 0.0035
+	BasisPoints
+	// Currency leading or trailing symbol or code: $1,000 or EUR 12.30
+	Currency
+)
+
+// scale10 multiplies d by 10^n changing exponent only.
+func scale10(d Dec64, n int64) (Dec64, error) {
+	mant := d.Coefficient()
+	if mant == 0 {
+		return d, nil
+	}
+	e := int64(d.Exponent())
+	e += n
+	for e < -127 && mant%10 == 0 {
+		mant /= 10
+		e++
+	}
+	for e > 127 && mant*10 <= MaxCoefficient && mant*10 >= -MaxCoefficient {
+		mant *= 10
+		e--
+	}
+	if e < -127 || e > 127 {
+		return Empty, fmt.Errorf("%s*10^%d is out of dec64 range", d, n)
+	}
+	return pack(mant, int(e)), nil
+}
+
+// currencyLen returns length of currency symbol or code at
+// the beginning of s (or at the end if last is true).
+func currencyLen(s string, last bool) (n int) {
+	for n < len(s) {
+		var (
+			r    rune
+			size int
+		)
+		if last {
+			r, size = utf8.DecodeLastRuneInString(s[:len(s)-n])
+		} else {
+			r, size = utf8.DecodeRuneInString(s[n:])
+		}
+		if !unicode.Is(unicode.Sc, r) && (r < 'A' || r > 'Z') {
+			break
+		}
+		n += size
+	}
+	return
+}
+
+// ParseNotation returns a dec64 from a string written with locale l,
+// accepting decorations enabled in n.
+// Percent and basis points are scaled: 12.5% is 0.125, 35bp is 0.0035.
+func ParseNotation(s string, l Locale, n Notation) (res Dec64, err error) {
+	var (
+		parens, neg bool
+		shift       int64
+	)
+	for {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			break
+		}
+		if n&Accounting != 0 && !parens && s[0] == '(' && s[len(s)-1] == ')' {
+			parens = true
+			s = s[1 : len(s)-1]
+			continue
+		}
+		if n&Percent != 0 && shift == 0 && strings.HasSuffix(s, "%") {
+			shift = -2
+			s = s[:len(s)-1]
+			continue
+		}
+		if n&BasisPoints != 0 && shift == 0 {
+			if strings.HasSuffix(s, "bps") {
+				shift = -4
+				s = s[:len(s)-3]
+				continue
+			}
+			if strings.HasSuffix(s, "bp") {
+				shift = -4
+				s = s[:len(s)-2]
+				continue
+			}
+		}
+		if n&Currency != 0 {
+			if c := currencyLen(s, false); c > 0 {
+				s = s[c:]
+				continue
+			}
+			if c := currencyLen(s, true); c > 0 {
+				s = s[:len(s)-c]
+				continue
+			}
+			// minus before symbol: -$1,000
+			if !neg && s[0] == '-' && currencyLen(s[1:], false) > 0 {
+				neg = true
+				s = s[1:]
+				continue
+			}
+		}
+		break
+	}
+	res, err = ParseLocale(s, l)
+	if err != nil {
+		return
+	}
+	if parens {
+		if neg || Signum(res) < 0 {
+			err = ParseError(errors.New("Negative number inside parentheses"))
+			return
+		}
+		neg = true
+	}
+	if neg {
+		res = res.Neg()
+	}
+	if shift != 0 {
+		res, err = scale10(res, shift)
+		if err != nil {
+			err = ParseError(err)
+		}
+	}
+	return
+}
+
+// FormatNotation returns d as a string written with locale l
+// and decorations enabled in n.
+// symbol is the currency written when n has Currency,
+// codes like EUR are separated from the number by a space.
+func (d Dec64) FormatNotation(l Locale, n Notation, symbol string) string {
+	if d == Empty {
+		return d.String()
+	}
+	// out of range scaled values are written unscaled without suffix
+	suffix := ""
+	switch {
+	case n&Percent != 0:
+		if scaled, err := scale10(d, 2); err == nil {
+			d, suffix = scaled, "%"
+		}
+	case n&BasisPoints != 0:
+		if scaled, err := scale10(d, 4); err == nil {
+			d, suffix = scaled, "bp"
+		}
+	}
+	neg := Signum(d) < 0
+	if neg {
+		d = d.Neg()
+	}
+	var sb strings.Builder
+	if neg && n&Accounting != 0 {
+		sb.WriteByte('(')
+	} else if neg && !l.MinusAfter {
+		sb.WriteString(l.minus())
+	}
+	if n&Currency != 0 && symbol != "" {
+		sb.WriteString(symbol)
+		if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteString(d.FormatLocale(l))
+	sb.WriteString(suffix)
+	if neg && n&Accounting != 0 {
+		sb.WriteByte(')')
+	} else if neg && l.MinusAfter {
+		sb.WriteString(l.minus())
+	}
+	return sb.String()
+}
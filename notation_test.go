@@ -0,0 +1,76 @@
+package dec64
+
+import "testing"
+
+func testOneNotation(t *testing.T, n Notation, s, ref string) {
+	d, err := ParseNotation(s, LocaleEN, n)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if d.String() != ref {
+		t.Errorf("ParseNotation(%s) is %s should be %s", s, d.String(), ref)
+	}
+}
+
+func TestParseNotation(t *testing.T) {
+	all := Accounting | Percent | BasisPoints | Currency
+	testOneNotation(t, all, "(1,250.00)", "-1250")
+	testOneNotation(t, Accounting, " ( 3.5 ) ", "-3.5")
+	testOneNotation(t, all, "12.5%", "0.125")
+	testOneNotation(t, Percent, "-0.5 %", "-0.005")
+	testOneNotation(t, all, "35bp", "0.0035")
+	testOneNotation(t, BasisPoints, "-2.5 bps", "-0.00025")
+	testOneNotation(t, all, "$1,000", "1000")
+	testOneNotation(t, all, "EUR 12.30", "12.3")
+	testOneNotation(t, all, "12.30 EUR", "12.3")
+	testOneNotation(t, all, "-$1,000", "-1000")
+	testOneNotation(t, all, "($1,250.50)", "-1250.5")
+	testOneNotation(t, all, "€-3", "-3")
+	testOneNotation(t, 0, "12.5", "12.5")
+	for _, s := range []string{"(-5)", "12.5%%", "$5"} {
+		n := all
+		if s == "$5" {
+			n = Percent
+		}
+		if d, err := ParseNotation(s, LocaleEN, n); err == nil {
+			t.Errorf("ParseNotation(%s) is %s should be in error", s, d.String())
+		}
+	}
+}
+
+func testOneFormatNotation(t *testing.T, n Notation, symbol, s, ref string) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	res := d.FormatNotation(LocaleEN, n, symbol)
+	if res != ref {
+		t.Errorf("FormatNotation(%s) is %s should be %s", s, res, ref)
+	}
+	back, err := ParseNotation(res, LocaleEN, n)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !back.Equal(d) {
+		t.Errorf("ParseNotation(%s) is %s should be %s", res, back, d)
+	}
+}
+
+func TestFormatNotation(t *testing.T) {
+	testOneFormatNotation(t, Accounting, "", "-1250.5", "(1,250.5)")
+	testOneFormatNotation(t, Accounting, "", "1250.5", "1,250.5")
+	testOneFormatNotation(t, Percent, "", "0.125", "12.5%")
+	testOneFormatNotation(t, BasisPoints, "", "0.0035", "35bp")
+	testOneFormatNotation(t, Currency, "$", "-1000", "-$1,000")
+	testOneFormatNotation(t, Currency|Accounting, "$", "-1000", "($1,000)")
+	testOneFormatNotation(t, Currency, "EUR", "12.3", "EUR 12.3")
+	huge := pack(MaxCoefficient, 127)
+	for _, n := range []Notation{Percent, BasisPoints} {
+		if res := huge.FormatNotation(LocaleEN, n, ""); res != huge.FormatLocale(LocaleEN) {
+			t.Errorf("FormatNotation(%s) is %s should be %s", huge, res, huge.FormatLocale(LocaleEN))
+		}
+	}
+}
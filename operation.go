@@ -3,9 +3,14 @@
 // it's more accurate to store some sort of decimals
 package dec64
 
+import (
+	"math/big"
+	"math/bits"
+)
+
 // Signum returns 1 if a > 0, -1 if a < 0, 0 if a == 0
 func Signum(d Dec64) int {
-	if (uint64(d) & MMask) == 0 {
+	if d.Coefficient() == 0 {
 		return 0
 	}
 	if int64(d) < 0 {
@@ -16,15 +21,11 @@ func Signum(d Dec64) int {
 
 // Round rounds to nearest, presicions is 10^n.
 func Round(d Dec64, n int64) Dec64 {
-	mant := int64(d) >> 8
+	mant := d.Coefficient()
 	if mant == 0 {
 		return 0
 	}
-	e := int64(d) & 0xff
-	if e > 127 {
-		// negative
-		e -= 256
-	}
+	e := int64(d.Exponent())
 	// Normalize
 	for mant%10 == 0 {
 		mant /= 10
@@ -44,7 +45,7 @@ func Round(d Dec64, n int64) Dec64 {
 		}
 		e++
 	}
-	return Dec64(mant<<8 | (e & 0xff))
+	return pack(mant, int(e))
 }
 
 // Keep on mantisse
@@ -53,55 +54,79 @@ const (
 	MOverflow = 0x0080000000000000
 )
 
-// MultInt64 multiplies Dec64 by an int64.
+// MultInt64 multiplies Dec64 by an int64, specials are unchanged.
 func (d *Dec64) MultInt64(i int64) Dec64 {
-	mant := (uint64(*d) & MMask) * uint64(i)
-	return Dec64(int64(mant) | (int64(*d) & 0xff))
+	if isSpecial(*d) {
+		return *d
+	}
+	return mult(d.Coefficient(), i, d.Exponent())
 }
 
-// Neg -> *-1
+// mult returns a*b*10^exp rounded half up when product has too many
+// digits, NaN when too big.
+func mult(a, b int64, exp int) Dec64 {
+	neg := (a < 0) != (b < 0)
+	ua, ub := uint64(a), uint64(b)
+	if a < 0 {
+		ua = -ua
+	}
+	if b < 0 {
+		ub = -ub
+	}
+	hi, lo := bits.Mul64(ua, ub)
+	var (
+		d   Dec64
+		err error
+	)
+	if hi == 0 {
+		d, err = fit(neg, lo, exp, false, RoundHalfUp)
+	} else {
+		c := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+		d, err = fitBig(neg, c.Or(c, new(big.Int).SetUint64(lo)), exp, false, RoundHalfUp)
+	}
+	if err == ErrRange {
+		return NaN
+	}
+	return d
+}
+
+// Neg -> *-1, zero and specials are unchanged.
 func (d Dec64) Neg() Dec64 {
-	mant := uint64(d) & MMask
-	return Dec64((-int64(mant)) | (int64(d) & 0xff))
+	if d.Coefficient() == 0 {
+		return d
+	}
+	return pack(-d.Coefficient(), d.Exponent())
 }
 
 // Add adds two dec64.
 func (d Dec64) Add(b Dec64) Dec64 {
-	ea := int64(d) & 0xff
-	eb := int64(b) & 0xff
+	ea := d.Exponent()
+	eb := b.Exponent()
 	if ea == eb {
 		// same exp, take care of overflow
-		coef := int64(d)>>8 + int64(b)>>8
+		coef := d.Coefficient() + b.Coefficient()
 		// overflow ?
 		if coef >= MOverflow || coef <= -MOverflow {
 			coef /= 10
 			ea++
 		}
-		return Dec64(coef<<8 | ea&0xff)
+		return pack(coef, ea)
 	}
 	// different exp
 	// first normalize
 	na := Normalize(d)
 	nb := Normalize(b)
-	ea = int64(na) & 0xff
-	if ea > 127 {
-		// negative
-		ea -= 256
-	}
-	eb = int64(nb) & 0xff
-	if eb > 127 {
-		// negative
-		eb -= 256
-	}
+	ea = na.Exponent()
+	eb = nb.Exponent()
 	if ea == eb {
 		// same exp, take care of overflow
-		coef := int64(na)>>8 + int64(nb)>>8
+		coef := na.Coefficient() + nb.Coefficient()
 		// overflow ?
 		if coef >= MOverflow || coef <= -MOverflow {
 			coef /= 10
 			ea++
 		}
-		return Dec64(coef<<8 | ea&0xff)
+		return pack(coef, ea)
 	}
 	if ea > eb {
 		// Switch to get ea > eb
@@ -109,7 +134,7 @@ func (d Dec64) Add(b Dec64) Dec64 {
 		ea, eb = eb, ea
 	}
 	var ncoef int64
-	coefb := int64(nb) >> 8
+	coefb := nb.Coefficient()
 	for (ea - eb) != 0 {
 		ncoef = coefb * 10
 		if (uint64(ncoef)^uint64(coefb))&0xff00000000000000 != 0 {
@@ -119,7 +144,7 @@ func (d Dec64) Add(b Dec64) Dec64 {
 		coefb = ncoef
 		eb--
 	}
-	coefa := int64(na) >> 8
+	coefa := na.Coefficient()
 	// overflow loose precision on a
 	if (eb - ea) != 0 {
 		if (eb - ea) >= 128 {
@@ -135,7 +160,7 @@ func (d Dec64) Add(b Dec64) Dec64 {
 		ncoef /= 10
 		ea++
 	}
-	return Dec64(ncoef<<8 | ea&0xff)
+	return pack(ncoef, ea)
 }
 
 // Sub substracts two dec64.
@@ -143,11 +168,17 @@ func (d Dec64) Sub(b Dec64) Dec64 {
 	return d.Add(b.Neg())
 }
 
-// Mult multiplies two dec64.
+// Mult multiplies two dec64 coefficients, rounding half up when product
+// has too many digits, NaN when too big.
+// Specials are returned unchanged.
 func (d Dec64) Mult(b Dec64) Dec64 {
-	mant := (uint64(d) & MMask) * (uint64(b) & MMask)
-	e := (int64(d) & 0xff) + (int64(b) & 0xff)
-	return Dec64(int64(mant) | e&0xff)
+	switch {
+	case isSpecial(d):
+		return d
+	case isSpecial(b):
+		return b
+	}
+	return mult(d.Coefficient(), b.Coefficient(), d.Exponent()+b.Exponent())
 }
 
 // Div TODO implement with dec64.
@@ -156,3 +187,92 @@ func (d Dec64) Div(b Dec64) (res Dec64) {
 	res, _ = FromFloat64(Float64(d) / Float64(b))
 	return
 }
+
+// RoundingMode tells how digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to nearest, ties away from zero as Round does.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to nearest, ties to even (IEEE 754 default).
+	RoundHalfEven
+	// RoundDown rounds toward zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+)
+
+// roundAway tells if absolute value must be incremented once digits
+// are dropped, rd is the first dropped digit and sticky tells that
+// following ones were not all zero.
+func (m RoundingMode) roundAway(neg, odd bool, rd uint64, sticky bool) bool {
+	if rd == 0 && !sticky {
+		// exact
+		return false
+	}
+	switch m {
+	case RoundHalfUp:
+		return rd >= 5
+	case RoundHalfEven:
+		return rd > 5 || (rd == 5 && (sticky || odd))
+	case RoundUp:
+		return true
+	case RoundFloor:
+		return neg
+	case RoundCeiling:
+		return !neg
+	}
+	return false
+}
+
+// fit returns a dec64 from an unsigned coefficient and an exponent,
+// dropping digits with rounding mode when they are too big.
+// sticky tells that non zero digits were already dropped.
+// ErrInexact is returned along with the rounded value when digits are lost.
+func fit(neg bool, c uint64, exp int, sticky bool, mode RoundingMode) (Dec64, error) {
+	// last dropped digit
+	var rd uint64
+	for {
+		for c > MaxCoefficient || (c != 0 && exp < -127) {
+			sticky = sticky || rd != 0
+			rd = c % 10
+			c /= 10
+			exp++
+		}
+		if mode.roundAway(neg, c&1 == 1, rd, sticky) {
+			c++
+			if c > MaxCoefficient {
+				// 99..9 rounded up, drop one more
+				continue
+			}
+		}
+		break
+	}
+	inexact := sticky || rd != 0
+	if c == 0 {
+		if inexact {
+			return 0, ErrInexact
+		}
+		return 0, nil
+	}
+	for exp > 127 {
+		if c*10 > MaxCoefficient {
+			return Empty, ErrRange
+		}
+		c *= 10
+		exp--
+	}
+	coef := int64(c)
+	if neg {
+		coef = -coef
+	}
+	res := word(coef, exp)
+	if inexact {
+		return res, ErrInexact
+	}
+	return res, nil
+}
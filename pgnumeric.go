@@ -0,0 +1,131 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// PostgreSQL NUMERIC sign values.
+const (
+	pgNumericPos = 0x0000
+	pgNumericNeg = 0x4000
+	pgNumericNaN = 0xC000
+)
+
+// floorDiv4 returns floor(n/4) for negative n too.
+func floorDiv4(n int) int {
+	if n < 0 {
+		return -((3 - n) / 4)
+	}
+	return n / 4
+}
+
+// PgNumeric returns d in PostgreSQL binary NUMERIC format
+// (as in COPY BINARY): ndigits, weight, sign and dscale
+// followed by base 10000 digits, all big endian.
+// Empty and NotAvailable have no NUMERIC value, NULL must be written instead.
+func PgNumeric(d Dec64) ([]byte, error) {
+	if d == Empty || d == NotAvailable {
+		return nil, errors.New("No NUMERIC value for Empty or NotAvailable")
+	}
+	b := make([]byte, 8, 8+2*6)
+	if d.IsNaN() {
+		binary.BigEndian.PutUint16(b[4:], pgNumericNaN)
+		return b, nil
+	}
+	neg, digits, exp := decompose(d)
+	if digits[0] == '0' {
+		return b, nil
+	}
+	if neg {
+		binary.BigEndian.PutUint16(b[4:], pgNumericNeg)
+	}
+	if exp < 0 {
+		binary.BigEndian.PutUint16(b[6:], uint16(-exp))
+	}
+	// digit i is for 10^(top-i)
+	top := len(digits) + exp - 1
+	weight := floorDiv4(top)
+	ndigits := weight - floorDiv4(exp) + 1
+	binary.BigEndian.PutUint16(b[0:], uint16(ndigits))
+	binary.BigEndian.PutUint16(b[2:], uint16(int16(weight)))
+	groups := make([]uint16, ndigits)
+	for i, c := range digits {
+		p := top - i
+		g := floorDiv4(p)
+		groups[weight-g] += uint16(c-'0') * uint16(Expi[p-4*g])
+	}
+	for _, g := range groups {
+		b = append(b, byte(g>>8), byte(g))
+	}
+	return b, nil
+}
+
+// FromPgNumeric returns a dec64 from PostgreSQL binary NUMERIC format,
+// ErrRange is returned when value cannot be stored exactly.
+func FromPgNumeric(b []byte) (res Dec64, err error) {
+	res = Empty
+	if len(b) < 8 {
+		err = fmt.Errorf("NUMERIC too short: %d bytes", len(b))
+		return
+	}
+	ndigits := int(binary.BigEndian.Uint16(b[0:]))
+	weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	if len(b) != 8+2*ndigits {
+		err = fmt.Errorf("NUMERIC with %d digits should be %d bytes not %d",
+			ndigits, 8+2*ndigits, len(b))
+		return
+	}
+	switch sign {
+	case pgNumericNaN:
+		res = NaN
+		return
+	case pgNumericPos, pgNumericNeg:
+	default:
+		err = ErrRange
+		return
+	}
+	// decimal digits, first is for 10^(4*weight+3)
+	digits := make([]byte, 0, 4*ndigits)
+	for i := 0; i < ndigits; i++ {
+		g := binary.BigEndian.Uint16(b[8+2*i:])
+		if g > 9999 {
+			err = fmt.Errorf("Wrong NUMERIC digit %d", g)
+			return
+		}
+		digits = append(digits, byte('0'+g/1000), byte('0'+g/100%10), byte('0'+g/10%10), byte('0'+g%10))
+	}
+	exp := 4 * (weight - ndigits + 1)
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		exp++
+	}
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		res = 0
+		return
+	}
+	if len(digits) > 17 {
+		err = ErrRange
+		return
+	}
+	coef, _ := strconv.ParseInt(string(digits), 10, 64)
+	for exp > 127 && coef*10 <= MaxCoefficient {
+		coef *= 10
+		exp--
+	}
+	if coef > MaxCoefficient || exp > 127 || exp < -127 {
+		err = ErrRange
+		return
+	}
+	if sign == pgNumericNeg {
+		coef = -coef
+	}
+	res = pack(coef, exp)
+	return
+}
@@ -0,0 +1,82 @@
+package dec64
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// pgBytes builds a binary NUMERIC.
+func pgBytes(weight int16, sign, dscale uint16, digits ...uint16) []byte {
+	b := make([]byte, 8+2*len(digits))
+	binary.BigEndian.PutUint16(b[0:], uint16(len(digits)))
+	binary.BigEndian.PutUint16(b[2:], uint16(weight))
+	binary.BigEndian.PutUint16(b[4:], sign)
+	binary.BigEndian.PutUint16(b[6:], dscale)
+	for i, d := range digits {
+		binary.BigEndian.PutUint16(b[8+2*i:], d)
+	}
+	return b
+}
+
+func testOnePgNumeric(t *testing.T, s string, ref []byte) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	b, err := PgNumeric(d)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !bytes.Equal(b, ref) {
+		t.Errorf("PgNumeric(%s) is %v should be %v", s, b, ref)
+	}
+	back, err := FromPgNumeric(b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if Normalize(back) != Normalize(d) {
+		t.Errorf("FromPgNumeric(%v) is %s should be %s", b, back, d)
+	}
+}
+
+func TestPgNumeric(t *testing.T) {
+	testOnePgNumeric(t, "0", pgBytes(0, pgNumericPos, 0))
+	testOnePgNumeric(t, "12345.678", pgBytes(1, pgNumericPos, 3, 1, 2345, 6780))
+	testOnePgNumeric(t, "-0.0001", pgBytes(-1, pgNumericNeg, 4, 1))
+	testOnePgNumeric(t, "0.00001", pgBytes(-2, pgNumericPos, 5, 1000))
+	testOnePgNumeric(t, "10000", pgBytes(1, pgNumericPos, 0, 1))
+	testOnePgNumeric(t, "99990000", pgBytes(1, pgNumericPos, 0, 9999))
+	testOnePgNumeric(t, "3.1997721799999996", pgBytes(0, pgNumericPos, 16, 3, 1997, 7217, 9999, 9996))
+	testOnePgNumeric(t, "1e120", pgBytes(30, pgNumericPos, 0, 1))
+	testOnePgNumeric(t, "-36028797018963967e-127",
+		pgBytes(-28, pgNumericNeg, 127, 36, 287, 9701, 8963, 9670))
+
+	b, err := PgNumeric(NaN)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if d, err := FromPgNumeric(b); err != nil || !d.IsNaN() {
+		t.Errorf("FromPgNumeric(%v) is %s, %v should be NaN", b, d, err)
+	}
+	if _, err := PgNumeric(Empty); err == nil {
+		t.Errorf("PgNumeric(Empty) should be in error")
+	}
+	// out of range or malformed
+	for _, b := range [][]byte{
+		pgBytes(0, pgNumericPos, 0, 1, 2, 3, 4, 5, 6),
+		pgBytes(40, pgNumericPos, 0, 1),
+		pgBytes(-40, pgNumericPos, 160, 1),
+		pgBytes(0, 0xD000, 0),
+		pgBytes(0, pgNumericPos, 0, 10000),
+		pgBytes(0, pgNumericPos, 0, 1)[:9],
+	} {
+		if d, err := FromPgNumeric(b); err == nil {
+			t.Errorf("FromPgNumeric(%v) is %s should be in error", b, d)
+		}
+	}
+}
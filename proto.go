@@ -0,0 +1,236 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Protocol buffers wire types used by the messages below.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// errProtoTruncated message ends inside a field.
+var errProtoTruncated = errors.New("Truncated protobuf message")
+
+// appendProtoVarint appends v as a base 128 varint.
+func appendProtoVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// appendProtoTag appends key of field with wire type.
+func appendProtoTag(b []byte, field, wire int) []byte {
+	return appendProtoVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+// appendProtoString appends a length delimited field.
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendProtoVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// zigzag encoding of sint32 and sint64
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// readProto calls f for each field of message b,
+// v is the value of varint fields and data the content of bytes fields.
+// Fixed fields are skipped.
+func readProto(b []byte, f func(field, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		b = b[n:]
+		field, wire := int(key>>3), int(key&7)
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire {
+		case protoVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+		case protoFixed64:
+			n = 8
+		case protoFixed32:
+			n = 4
+		case protoBytes:
+			v, n = binary.Uvarint(b)
+			if n <= 0 || v > uint64(len(b)-n) {
+				return errProtoTruncated
+			}
+			data = b[n : n+int(v)]
+			n += int(v)
+		default:
+			return fmt.Errorf("Unsupported protobuf wire type %d", wire)
+		}
+		if n > len(b) {
+			return errProtoTruncated
+		}
+		b = b[n:]
+		if field == 0 {
+			return errors.New("Invalid protobuf field 0")
+		}
+		if err := f(field, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendProto appends d as the protobuf message
+//
+//	Decimal { sint64 coefficient = 1; sint32 exponent = 2; }
+//
+// zero fields are omitted, Empty, NotAvailable and NaN round trip.
+func AppendProto(b []byte, d Dec64) []byte {
+	if coef := d.Coefficient(); coef != 0 {
+		b = appendProtoTag(b, 1, protoVarint)
+		b = appendProtoVarint(b, zigzag(coef))
+	}
+	if exp := d.Exponent(); exp != 0 {
+		b = appendProtoTag(b, 2, protoVarint)
+		b = appendProtoVarint(b, zigzag(int64(exp)))
+	}
+	return b
+}
+
+// FromProto returns dec64 from a Decimal message written by AppendProto,
+// unknown fields are ignored, ErrRange is returned when
+// coefficient or exponent do not fit.
+func FromProto(b []byte) (res Dec64, err error) {
+	var coef, exp int64
+	err = readProto(b, func(field, wire int, v uint64, data []byte) error {
+		if wire != protoVarint {
+			return nil
+		}
+		switch field {
+		case 1:
+			coef = unzigzag(v)
+		case 2:
+			exp = unzigzag(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	if coef > MaxCoefficient || coef < MinCoefficient || exp < -128 || exp > 127 {
+		return Empty, ErrRange
+	}
+	res = word(coef, int(exp))
+	return
+}
+
+// AppendGoogleDecimal appends d as a google.type.Decimal message,
+// value string in field 1. Empty is an empty message,
+// ErrRange is returned for NotAvailable and NaN.
+func AppendGoogleDecimal(b []byte, d Dec64) ([]byte, error) {
+	switch {
+	case d == Empty:
+		return b, nil
+	case d == NotAvailable || d.IsNaN():
+		return b, ErrRange
+	}
+	return appendProtoString(b, 1, d.String()), nil
+}
+
+// FromGoogleDecimal returns dec64 from a google.type.Decimal message,
+// missing value is Empty.
+func FromGoogleDecimal(b []byte) (res Dec64, err error) {
+	var value string
+	err = readProto(b, func(field, wire int, v uint64, data []byte) error {
+		if field == 1 && wire == protoBytes {
+			value = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	return Parse(value)
+}
+
+// nanosPerUnit google.type.Money nanos are 10^-9 units.
+const nanosPerUnit = 1000000000
+
+// AppendGoogleMoney appends d as a google.type.Money message
+// { string currency_code = 1; int64 units = 2; int32 nanos = 3; }.
+// d is rounded to nanos with mode, ErrInexact is returned along with
+// the message when digits are lost, ErrRange for Empty, NotAvailable,
+// NaN or when units do not fit in int64.
+func AppendGoogleMoney(b []byte, d Dec64, currency string, mode RoundingMode) ([]byte, error) {
+	coef, err := ToScaledBigInt(d, 9, mode)
+	if err != nil && err != ErrInexact {
+		return b, err
+	}
+	units, nanos := coef.QuoRem(coef, big.NewInt(nanosPerUnit), new(big.Int))
+	if !units.IsInt64() {
+		return b, ErrRange
+	}
+	if currency != "" {
+		b = appendProtoString(b, 1, currency)
+	}
+	if u := units.Int64(); u != 0 {
+		b = appendProtoTag(b, 2, protoVarint)
+		b = appendProtoVarint(b, uint64(u))
+	}
+	if n := nanos.Int64(); n != 0 {
+		b = appendProtoTag(b, 3, protoVarint)
+		b = appendProtoVarint(b, uint64(n))
+	}
+	return b, err
+}
+
+// FromGoogleMoney returns amount and currency code of a
+// google.type.Money message. nanos must be in [-999999999, 999999999]
+// with the sign of units. ErrInexact is returned along with the
+// rounded value when amount has more than 17 digits.
+func FromGoogleMoney(b []byte) (res Dec64, currency string, err error) {
+	var units, nanos int64
+	err = readProto(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == protoBytes:
+			currency = string(data)
+		case field == 2 && wire == protoVarint:
+			units = int64(v)
+		case field == 3 && wire == protoVarint:
+			// int32 is sign extended on the wire
+			nanos = int64(int32(v))
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	if nanos <= -nanosPerUnit || nanos >= nanosPerUnit ||
+		(units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		err = fmt.Errorf("Invalid money %d units %d nanos", units, nanos)
+		return
+	}
+	v := new(big.Int).Mul(big.NewInt(units), big.NewInt(nanosPerUnit))
+	v.Add(v, big.NewInt(nanos))
+	res, err = FromBigInt(v, -9)
+	if err == ErrRange {
+		return
+	}
+	res = Normalize(res)
+	return
+}
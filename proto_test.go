@@ -0,0 +1,100 @@
+package dec64
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testProto(t *testing.T, d Dec64, ref []byte) {
+	b := AppendProto(nil, d)
+	if !bytes.Equal(b, ref) {
+		t.Errorf("%s encoded as %x should be %x", d, b, ref)
+	}
+	res, err := FromProto(b)
+	if err != nil || res != d {
+		t.Errorf("%x decoded as %s (%v) should be %s", b, res, err, d)
+	}
+}
+
+func TestProto(t *testing.T) {
+	// coefficient 12345 zigzag 24690, exponent -2 zigzag 3
+	testProto(t, Dec64(12345*256+254), []byte{0x08, 0xf2, 0xc0, 0x01, 0x10, 0x03})
+	testProto(t, Dec64(-1*256), []byte{0x08, 0x01})
+	testProto(t, 0, []byte{})
+	testProto(t, Empty, []byte{0x10, 0x02})
+	testProto(t, NaN, []byte{0x10, 0xff, 0x01})
+	testProto(t, NotAvailable, []byte{0x10, 0x01})
+	// unknown fields skipped
+	res, err := FromProto([]byte{0x1a, 0x02, 'h', 'i', 0x08, 0x0a, 0x25, 1, 2, 3, 4})
+	if err != nil || res != Dec64(5*256) {
+		t.Errorf("Unknown fields gives %s (%v)", res, err)
+	}
+	if _, err = FromProto([]byte{0x08}); err == nil {
+		t.Errorf("Truncated message should be an error")
+	}
+	if _, err = FromProto([]byte{0x10, 0x80, 0x02}); err != ErrRange {
+		t.Errorf("Exponent 128 gives %v", err)
+	}
+}
+
+func TestGoogleDecimal(t *testing.T) {
+	d, _ := Parse("-1.25")
+	b, err := AppendGoogleDecimal(nil, d)
+	if err != nil || !bytes.Equal(b, []byte{0x0a, 0x05, '-', '1', '.', '2', '5'}) {
+		t.Errorf("-1.25 encoded as %x (%v)", b, err)
+	}
+	res, err := FromGoogleDecimal([]byte{0x0a, 0x05, '1', '.', '5', 'e', '3'})
+	if err != nil || res.String() != "1500" {
+		t.Errorf("1.5e3 decoded as %s (%v)", res, err)
+	}
+	if res, err = FromGoogleDecimal(nil); err != nil || res != Empty {
+		t.Errorf("Empty message decoded as %s (%v)", res, err)
+	}
+	if res, err = FromGoogleDecimal([]byte{0x0a, 0x01, ' '}); err == nil {
+		t.Errorf("blank value decoded as %s should be in error", res)
+	}
+	if _, err = AppendGoogleDecimal(nil, NaN); err != ErrRange {
+		t.Errorf("NaN gives %v", err)
+	}
+}
+
+func testMoney(t *testing.T, s string, mode RoundingMode, ref string, referr error) {
+	d, _ := Parse(s)
+	b, err := AppendGoogleMoney(nil, d, "EUR", mode)
+	if err != referr {
+		t.Errorf("%s encoding error is %v should be %v", s, err, referr)
+	}
+	if err == ErrRange {
+		return
+	}
+	res, currency, err := FromGoogleMoney(b)
+	if err != nil || currency != "EUR" || res.String() != ref {
+		t.Errorf("%s gives %s %s (%v) should be %s", s, res, currency, err, ref)
+	}
+}
+
+func TestGoogleMoney(t *testing.T) {
+	testMoney(t, "1.75", RoundHalfUp, "1.75", nil)
+	testMoney(t, "-1.75", RoundHalfUp, "-1.75", nil)
+	testMoney(t, "0", RoundHalfUp, "0", nil)
+	testMoney(t, "123456789", RoundHalfUp, "123456789", nil)
+	testMoney(t, "0.0000000015", RoundHalfUp, "0.000000002", ErrInexact)
+	testMoney(t, "0.0000000025", RoundHalfEven, "0.000000002", ErrInexact)
+	testMoney(t, "-0.0000000015", RoundFloor, "-0.000000002", ErrInexact)
+	testMoney(t, "1e100", RoundHalfUp, "", ErrRange)
+	// -1.75 EUR: units -1, nanos -750000000
+	b := []byte{0x0a, 3, 'E', 'U', 'R', 0x10, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01,
+		0x18, 0x80, 0xd1, 0xaf, 0x9a, 0xfd, 0xff, 0xff, 0xff, 0xff, 0x01}
+	res, currency, err := FromGoogleMoney(b)
+	if err != nil || currency != "EUR" || res.String() != "-1.75" {
+		t.Errorf("-1.75 EUR decoded as %s %s (%v)", res, currency, err)
+	}
+	d, _ := Parse("-1.75")
+	if enc, _ := AppendGoogleMoney(nil, d, "EUR", RoundHalfUp); !bytes.Equal(enc, b) {
+		t.Errorf("-1.75 EUR encoded as %x should be %x", enc, b)
+	}
+	// mixed signs
+	if _, _, err = FromGoogleMoney([]byte{0x10, 0x01, 0x18, 0xff, 0xff, 0xff, 0xff, 0x0f}); err == nil {
+		t.Errorf("Mixed signs should be an error")
+	}
+}
@@ -0,0 +1,100 @@
+package dec64
+
+import (
+	"math"
+	"math/bits"
+)
+
+// FromScaledInt returns v*10^-scale, as prices with implied decimals
+// sent by exchanges: FromScaledInt(1012500, 4) is 101.25.
+// ErrInexact is returned along with the rounded value (half up)
+// when v has too many digits.
+func FromScaledInt(v int64, scale int) (Dec64, error) {
+	u := uint64(v)
+	if v < 0 {
+		u = -u
+	}
+	return fit(v < 0, u, -scale, false, RoundHalfUp)
+}
+
+// ToScaledInt returns d*10^scale as an integer rounded with mode:
+// ToScaledInt(101.25, 4, RoundHalfUp) is 1012500.
+// ErrInexact is returned along with the rounded value when digits are lost,
+// ErrRange when result does not fit in an int64 or d is not a number.
+func ToScaledInt(d Dec64, scale int, mode RoundingMode) (int64, error) {
+	if isSpecial(d) {
+		return 0, ErrRange
+	}
+	sign, coef, exp := signCoef(d)
+	neg := sign == 1
+	if coef == 0 {
+		return 0, nil
+	}
+	k := exp + scale
+	if k >= 0 {
+		if k > 18 {
+			return 0, ErrRange
+		}
+		hi, lo := bits.Mul64(coef, uint64(Expi[k]))
+		if hi != 0 || lo > math.MaxInt64 {
+			return 0, ErrRange
+		}
+		if neg {
+			return -int64(lo), nil
+		}
+		return int64(lo), nil
+	}
+	// drop -k digits
+	var (
+		q, rd  uint64
+		sticky = true
+	)
+	// coefficient has at most 17 digits
+	if -k < 18 {
+		p := uint64(Expi[-k])
+		q = coef / p
+		r := coef % p
+		rd = r / (p / 10)
+		sticky = r%(p/10) != 0
+	}
+	if mode.roundAway(neg, q&1 == 1, rd, sticky) {
+		q++
+	}
+	res := int64(q)
+	if neg {
+		res = -res
+	}
+	if rd != 0 || sticky {
+		return res, ErrInexact
+	}
+	return res, nil
+}
+
+// FromScaledInts converts a column of scaled integers as FromScaledInt does.
+// Conversion stops on first error but ErrInexact which is reported
+// for the first rounded value once all values are converted.
+// Errors are wrapped with the index of the value, use errors.Is.
+func FromScaledInts(values []int64, scale int) ([]Dec64, error) {
+	res := make([]Dec64, len(values))
+	n, err := convertAll(len(values), func(i int) (err error) {
+		// exact values need no rounding
+		if res[i], err = New(values[i], -scale); err != nil {
+			res[i], err = FromScaledInt(values[i], scale)
+		}
+		return
+	})
+	return res[:n], err
+}
+
+// ToScaledInts converts a column of dec64 as ToScaledInt does.
+// Conversion stops on first error but ErrInexact which is reported
+// for the first rounded value once all values are converted.
+// Errors are wrapped with the index of the value, use errors.Is.
+func ToScaledInts(values []Dec64, scale int, mode RoundingMode) ([]int64, error) {
+	res := make([]int64, len(values))
+	n, err := convertAll(len(values), func(i int) (err error) {
+		res[i], err = ToScaledInt(values[i], scale, mode)
+		return
+	})
+	return res[:n], err
+}
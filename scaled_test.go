@@ -0,0 +1,80 @@
+package dec64
+
+import (
+	"errors"
+	"testing"
+)
+
+func testOneToScaled(t *testing.T, s string, scale int, mode RoundingMode, ref int64, referr error) {
+	d, err := Parse(s)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	v, err := ToScaledInt(d, scale, mode)
+	if err != referr {
+		t.Errorf("ToScaledInt(%s, %d, %d) error is %v should be %v", s, scale, mode, err, referr)
+	}
+	if v != ref {
+		t.Errorf("ToScaledInt(%s, %d, %d) is %d should be %d", s, scale, mode, v, ref)
+	}
+}
+
+func TestToScaledInt(t *testing.T) {
+	testOneToScaled(t, "101.25", 4, RoundHalfUp, 1012500, nil)
+	testOneToScaled(t, "-0.00000001", 8, RoundHalfUp, -1, nil)
+	testOneToScaled(t, "1e10", 9, RoundHalfUp, 0, ErrRange)
+	testOneToScaled(t, "1e9", 9, RoundHalfUp, 1000000000000000000, nil)
+	testOneToScaled(t, "2.5", 0, RoundHalfUp, 3, ErrInexact)
+	testOneToScaled(t, "2.5", 0, RoundHalfEven, 2, ErrInexact)
+	testOneToScaled(t, "3.5", 0, RoundHalfEven, 4, ErrInexact)
+	testOneToScaled(t, "-2.5", 0, RoundHalfUp, -3, ErrInexact)
+	testOneToScaled(t, "2.1", 0, RoundUp, 3, ErrInexact)
+	testOneToScaled(t, "-2.9", 0, RoundDown, -2, ErrInexact)
+	testOneToScaled(t, "-2.1", 0, RoundFloor, -3, ErrInexact)
+	testOneToScaled(t, "-2.9", 0, RoundCeiling, -2, ErrInexact)
+	testOneToScaled(t, "1e-30", 2, RoundCeiling, 1, ErrInexact)
+	testOneToScaled(t, "1e-30", 2, RoundHalfUp, 0, ErrInexact)
+	testOneToScaled(t, "0.123456789", 9, RoundDown, 123456789, nil)
+}
+
+func TestFromScaledInts(t *testing.T) {
+	d, err := FromScaledInt(1012500, 4)
+	if err != nil || d.String() != "101.25" {
+		t.Errorf("FromScaledInt(1012500, 4) is %s, %v", d, err)
+	}
+	// 19 digits must be rounded
+	d, err = FromScaledInt(1234567890123456789, 9)
+	if err != ErrInexact || d.String() != "1234567890.1234568" {
+		t.Errorf("FromScaledInt(1234567890123456789, 9) is %s, %v", d, err)
+	}
+	values, err := FromScaledInts([]int64{12345678, -1, 0, 9223372036854775807}, 8)
+	if !errors.Is(err, ErrInexact) {
+		t.Errorf("FromScaledInts error is %v should be %v", err, ErrInexact)
+	}
+	refs := []string{"0.12345678", "-0.00000001", "0", "92233720368.54776"}
+	for i, ref := range refs {
+		if values[i].String() != ref {
+			t.Errorf("values[%d] is %s should be %s", i, values[i], ref)
+		}
+	}
+	// zero is never a special whatever scale
+	for _, scale := range []int{-1, 0, 1} {
+		values, err := FromScaledInts([]int64{0, 5}, scale)
+		if err != nil || values[0] != 0 {
+			t.Errorf("FromScaledInts([0], %d) is %#x, %v should be 0", scale, int64(values[0]), err)
+		}
+	}
+	ints, err := ToScaledInts(values[:3], 8, RoundHalfEven)
+	if err != nil {
+		t.Error(err)
+	}
+	for i, ref := range []int64{12345678, -1, 0} {
+		if ints[i] != ref {
+			t.Errorf("ints[%d] is %d should be %d", i, ints[i], ref)
+		}
+	}
+	if _, err = ToScaledInts([]Dec64{Dec64(256), NaN}, 2, RoundHalfUp); !errors.Is(err, ErrRange) {
+		t.Errorf("ToScaledInts error is %v should be %v", err, ErrRange)
+	}
+}
@@ -0,0 +1,71 @@
+package dec64
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Scan implements sql.Scanner for string, []byte, int64, float64 and nil.
+// NULL is Empty. ErrInexact is returned along with the rounded value
+// for integers that do not fit in 56 bits.
+func (d *Dec64) Scan(src interface{}) (err error) {
+	switch v := src.(type) {
+	case nil:
+		*d = Empty
+	case string:
+		*d, err = parseSQL(v)
+	case []byte:
+		*d, err = parseSQL(string(v))
+	case int64:
+		*d, err = FromScaledInt(v, 0)
+	case float64:
+		*d, err = FromFloat64(v)
+	default:
+		err = fmt.Errorf("Unable to scan %T into Dec64", src)
+	}
+	return
+}
+
+// parseSQL parses NUMERIC text, including NaN.
+func parseSQL(s string) (Dec64, error) {
+	if strings.EqualFold(s, "NaN") {
+		return NaN, nil
+	}
+	return Parse(s)
+}
+
+// Value implements driver.Valuer, Empty and NotAvailable are NULL.
+func (d Dec64) Value() (driver.Value, error) {
+	switch {
+	case d == Empty || d == NotAvailable:
+		return nil, nil
+	case d.IsNaN():
+		return "NaN", nil
+	}
+	return d.String(), nil
+}
+
+// NullDec64 is a Dec64 that may be NULL, as sql.NullInt64.
+type NullDec64 struct {
+	Dec64 Dec64
+	Valid bool // Valid is true if Dec64 is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullDec64) Scan(src interface{}) error {
+	if src == nil {
+		n.Dec64, n.Valid = Empty, false
+		return nil
+	}
+	n.Valid = true
+	return n.Dec64.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullDec64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Dec64.Value()
+}
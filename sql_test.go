@@ -0,0 +1,127 @@
+package dec64
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// fakeDriver returns rows of one column and records exec arguments.
+type fakeDriver struct {
+	rows []driver.Value
+	args []driver.Value
+}
+
+func (f *fakeDriver) Open(name string) (driver.Conn, error) { return f, nil }
+func (f *fakeDriver) Prepare(query string) (driver.Stmt, error) {
+	return f, nil
+}
+func (f *fakeDriver) Close() error              { return nil }
+func (f *fakeDriver) Begin() (driver.Tx, error) { return nil, errors.New("no tx") }
+func (f *fakeDriver) NumInput() int             { return -1 }
+func (f *fakeDriver) Exec(args []driver.Value) (driver.Result, error) {
+	f.args = append(f.args, args...)
+	return driver.RowsAffected(len(args)), nil
+}
+func (f *fakeDriver) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{values: f.rows}, nil
+}
+
+type fakeRows struct {
+	values []driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"price"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("dec64fake", fake)
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("dec64fake", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer db.Close()
+	fake.rows = []driver.Value{"101.25", []byte("-0.5"), int64(42), float64(0.25), nil, "NaN"}
+	refs := []string{"101.25", "-0.5", "42", "0.25", "null", ""}
+	rows, err := db.Query("SELECT price")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i := 0; rows.Next(); i++ {
+		var d Dec64
+		if err = rows.Scan(&d); err != nil {
+			t.Error(err)
+			continue
+		}
+		if refs[i] == "" {
+			if !d.IsNaN() {
+				t.Errorf("row %d is %s should be NaN", i, d)
+			}
+			continue
+		}
+		if d.String() != refs[i] {
+			t.Errorf("row %d is %s should be %s", i, d, refs[i])
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.Error(err)
+	}
+	fake.rows = []driver.Value{"1.5", nil}
+	rows, err = db.Query("SELECT price")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var nulls []NullDec64
+	for rows.Next() {
+		var n NullDec64
+		if err = rows.Scan(&n); err != nil {
+			t.Error(err)
+		}
+		nulls = append(nulls, n)
+	}
+	if len(nulls) != 2 || !nulls[0].Valid || nulls[0].Dec64.String() != "1.5" || nulls[1].Valid {
+		t.Errorf("nulls are %v", nulls)
+	}
+	_, err = db.Exec("INSERT", Dec64(125*256+254), Empty, NaN, NullDec64{Dec64: Dec64(256), Valid: true}, NullDec64{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	args := []driver.Value{"1.25", nil, "NaN", "1", nil}
+	if len(fake.args) != len(args) {
+		t.Errorf("args are %v should be %v", fake.args, args)
+		return
+	}
+	for i, arg := range args {
+		if fake.args[i] != arg {
+			t.Errorf("args[%d] is %v should be %v", i, fake.args[i], arg)
+		}
+	}
+	var d Dec64
+	if err = d.Scan(true); err == nil {
+		t.Errorf("Scan(true) should be in error")
+	}
+	if err = d.Scan(int64(-42)); err != nil || d.String() != "-42" {
+		t.Errorf("Scan(-42) is %s (%v)", d, err)
+	}
+	if err = d.Scan(int64(1 << 62)); err != ErrInexact || d.String() != "4611686018427388000" {
+		t.Errorf("Scan(1<<62) is %s (%v) should be rounded with %v", d, err, ErrInexact)
+	}
+}
@@ -0,0 +1,147 @@
+package dec64
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrPartialRecord input ends inside a value.
+var ErrPartialRecord = errors.New("Partial dec64 record at end of input")
+
+// streamBufferSize bytes buffered by Decoder and Encoder.
+const streamBufferSize = 8 * 512
+
+// Decoder reads dec64 values written as raw 8 bytes words.
+type Decoder struct {
+	// Order of values, little endian by default.
+	Order binary.ByteOrder
+
+	r          io.Reader
+	buf        []byte
+	start, end int
+	err        error
+}
+
+// NewDecoder returns a decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{Order: binary.LittleEndian, r: r, buf: make([]byte, streamBufferSize)}
+}
+
+// fill reads until at least one value is buffered or r fails.
+func (d *Decoder) fill() {
+	if d.start > 0 {
+		d.end = copy(d.buf, d.buf[d.start:d.end])
+		d.start = 0
+	}
+	for d.end < 8 && d.err == nil {
+		var n int
+		n, d.err = d.r.Read(d.buf[d.end:])
+		d.end += n
+	}
+}
+
+// error returns error once buffered values are read,
+// io.EOF only if input ends on a value boundary.
+func (d *Decoder) error() error {
+	if d.err == io.EOF && d.end > d.start {
+		return ErrPartialRecord
+	}
+	return d.err
+}
+
+// Next returns next value, io.EOF at end of input
+// and ErrPartialRecord if it ends inside a value.
+func (d *Decoder) Next() (Dec64, error) {
+	if d.end-d.start < 8 {
+		d.fill()
+		if d.end-d.start < 8 {
+			return Empty, d.error()
+		}
+	}
+	v := Dec64(d.Order.Uint64(d.buf[d.start:]))
+	d.start += 8
+	return v, nil
+}
+
+// ReadBatch reads up to len(buf) values into buf and returns their number,
+// it does not wait for more values once some are available as io.Reader.
+// io.EOF is returned with no value at end of input,
+// ErrPartialRecord if it ends inside a value.
+func (d *Decoder) ReadBatch(buf []Dec64) (n int, err error) {
+	if len(buf) == 0 {
+		return
+	}
+	if d.end-d.start < 8 {
+		d.fill()
+		if d.end-d.start < 8 {
+			return 0, d.error()
+		}
+	}
+	for ; n < len(buf) && d.end-d.start >= 8; n++ {
+		buf[n] = Dec64(d.Order.Uint64(d.buf[d.start:]))
+		d.start += 8
+	}
+	return
+}
+
+// ReadAll reads values until end of input, error is nil
+// when input ends on a value boundary.
+func (d *Decoder) ReadAll() (values []Dec64, err error) {
+	// small capacity to start
+	values = make([]Dec64, 0, 16)
+	buf := make([]Dec64, streamBufferSize/8)
+	for {
+		var n int
+		n, err = d.ReadBatch(buf)
+		values = append(values, buf[:n]...)
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Encoder writes dec64 values as raw 8 bytes words,
+// Flush must be called once all values are written.
+type Encoder struct {
+	// Order of values, little endian by default.
+	Order binary.ByteOrder
+
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{Order: binary.LittleEndian, w: w, buf: make([]byte, 0, streamBufferSize)}
+}
+
+// Write buffers values, previous write error is returned.
+func (e *Encoder) Write(values ...Dec64) error {
+	for _, v := range values {
+		if e.err != nil {
+			return e.err
+		}
+		if len(e.buf)+8 > cap(e.buf) {
+			e.Flush()
+		}
+		n := len(e.buf)
+		e.buf = e.buf[:n+8]
+		e.Order.PutUint64(e.buf[n:], uint64(v))
+	}
+	return e.err
+}
+
+// Flush writes buffered values.
+func (e *Encoder) Flush() error {
+	if e.err != nil || len(e.buf) == 0 {
+		return e.err
+	}
+	_, e.err = e.w.Write(e.buf)
+	e.buf = e.buf[:0]
+	return e.err
+}
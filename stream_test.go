@@ -0,0 +1,117 @@
+package dec64
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func streamValues(n int) []Dec64 {
+	values := make([]Dec64, n)
+	for i := range values {
+		values[i] = Dec64(int64(i*13-700)<<8 | 0xfd)
+	}
+	return values
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	values := streamValues(1500)
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	for _, v := range values {
+		if err := e.Write(v); err != nil {
+			t.Errorf("Write: %v", err)
+		}
+	}
+	if buf.Len() == 0 || buf.Len() == 8*len(values) {
+		t.Errorf("Encoder should buffer, %d bytes written", buf.Len())
+	}
+	if err := e.Flush(); err != nil || buf.Len() != 8*len(values) {
+		t.Errorf("Flush gives %d bytes (%v)", buf.Len(), err)
+	}
+	data := buf.Bytes()
+	// one by one, even from a slow reader
+	d := NewDecoder(iotest.OneByteReader(bytes.NewReader(data)))
+	for i, value := range values {
+		v, err := d.Next()
+		if err != nil || v != value {
+			t.Errorf("values[%d] is %s (%v) should be %s", i, v, err, value)
+			return
+		}
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("End gives %v", err)
+	}
+	// by batch
+	d = NewDecoder(bytes.NewReader(data))
+	batch := make([]Dec64, 100)
+	read := 0
+	for {
+		n, err := d.ReadBatch(batch)
+		for i := 0; i < n; i++ {
+			if batch[i] != values[read+i] {
+				t.Errorf("values[%d] is %s should be %s", read+i, batch[i], values[read+i])
+			}
+		}
+		read += n
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Errorf("ReadBatch: %v", err)
+			return
+		}
+	}
+	if read != len(values) {
+		t.Errorf("%d values read should be %d", read, len(values))
+	}
+}
+
+func TestPartialRecord(t *testing.T) {
+	var buf bytes.Buffer
+	ListToWriter(&buf, streamValues(3))
+	data := buf.Bytes()[:20]
+	d := NewDecoder(bytes.NewReader(data))
+	for i := 0; i < 2; i++ {
+		if _, err := d.Next(); err != nil {
+			t.Errorf("values[%d]: %v", i, err)
+		}
+	}
+	if _, err := d.Next(); err != ErrPartialRecord {
+		t.Errorf("Partial record gives %v", err)
+	}
+	values, err := ListFromReader(bytes.NewReader(data))
+	if err != ErrPartialRecord || len(values) != 2 {
+		t.Errorf("ListFromReader gives %d values (%v)", len(values), err)
+	}
+	values, err = ListFromReader(bytes.NewReader(nil))
+	if err != nil || len(values) != 0 {
+		t.Errorf("Empty input gives %d values (%v)", len(values), err)
+	}
+}
+
+type failWriter struct{}
+
+var errFail = errors.New("fail")
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errFail
+}
+
+func TestEncoderError(t *testing.T) {
+	e := NewEncoder(failWriter{})
+	if err := e.Write(streamValues(10)...); err != nil {
+		t.Errorf("Buffered write gives %v", err)
+	}
+	if err := e.Flush(); err != errFail {
+		t.Errorf("Flush gives %v", err)
+	}
+	if err := e.Write(Empty); err != errFail {
+		t.Errorf("Write after error gives %v", err)
+	}
+	if err := ListToWriter(failWriter{}, streamValues(1)); err != errFail {
+		t.Errorf("ListToWriter gives %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package dec64
+
+// ColumnFormatter renders a column of dec64 aligned on the decimal point.
+type ColumnFormatter struct {
+	// MaxDecimals limits digits after the dot when positive.
+	MaxDecimals int
+	// Width minimum width of cells.
+	Width int
+	// Null written for Empty, NotAvailable and NaN values.
+	Null string
+}
+
+// Format returns values as right justified strings of the same width,
+// all written with the number of decimals of the smaller exponent
+// (as Homogenize would choose) so that dots are aligned.
+func (f ColumnFormatter) Format(values []Dec64) []string {
+	decimals := 0
+	if exp := minExponent(numbers(values)); exp < 0 {
+		decimals = int(-exp)
+	}
+	if f.MaxDecimals > 0 && decimals > f.MaxDecimals {
+		decimals = f.MaxDecimals
+	}
+	cells := make([]string, len(values))
+	width := f.Width
+	buf := make([]byte, 0, 32)
+	for i, d := range values {
+		if isSpecial(d) {
+			cells[i] = f.Null
+		} else {
+			buf = appendFixed(buf[:0], d, decimals)
+			cells[i] = string(buf)
+		}
+		if len(cells[i]) > width {
+			width = len(cells[i])
+		}
+	}
+	for i, c := range cells {
+		cells[i] = pad(c, width)
+	}
+	return cells
+}
@@ -0,0 +1,39 @@
+package dec64
+
+import "testing"
+
+func TestColumnFormatter(t *testing.T) {
+	values := make([]Dec64, 0, 6)
+	for _, s := range []string{"101.25", "2", "-0.5", "1234.125", "0"} {
+		d, err := Parse(s)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		values = append(values, d)
+	}
+	// NaN with a coefficient must not change decimals
+	values = append(values, Empty, Dec64(5<<8|0x80))
+	refs := []string{
+		"  101.250",
+		"    2.000",
+		"   -0.500",
+		" 1234.125",
+		"    0.000",
+		"        -",
+		"        -",
+	}
+	cells := ColumnFormatter{Width: 9, Null: "-"}.Format(values)
+	for i, ref := range refs {
+		if cells[i] != ref {
+			t.Errorf("cells[%d] is '%s' should be '%s'", i, cells[i], ref)
+		}
+	}
+	refs = []string{"101.3", "2.0", "-0.5", "1234.1", "0.0", "", ""}
+	cells = ColumnFormatter{MaxDecimals: 1}.Format(values)
+	for i, ref := range refs {
+		if cells[i] != pad(ref, 6) {
+			t.Errorf("cells[%d] is '%s' should be '%s'", i, cells[i], pad(ref, 6))
+		}
+	}
+}